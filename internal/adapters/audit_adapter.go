@@ -0,0 +1,160 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type contextActor string
+
+// ActorId es la llave del contexto con el usuario que realiza los cambios, se guarda en la tabla de auditoría
+const ActorId contextActor = "actorId"
+
+// defaultAuditTable es la tabla de auditoría si no se configura ConfigPgxAdapter.AuditTable ni ENV_DB_AUDIT_TABLE
+const defaultAuditTable = "audit_log"
+
+// MaxParameters es el limite de parámetros ($n) que acepta PostgreSQL en una sentencia
+const MaxParameters = 65535
+
+// auditColumns son las columnas de la tabla de auditoría que recibe un parámetro, created_at se establece con now()
+var auditColumns = []string{"table_name", "record_key", "action", "changes", "actor"}
+
+// AuditExec configura la auditoría de una sentencia, las sentencias auditadas siempre se ejecutan con RETURNING *
+type AuditExec struct {
+	Keys   []string // columnas de la llave primaria, identifican el registro auditado
+	Query  string   // SELECT previo (FOR UPDATE) de los registros que serán modificados, vació si no hay valores anteriores
+	Values []any    // valores del SELECT previo
+}
+
+// auditEntry es un registro de la tabla de auditoría
+type auditEntry struct {
+	table   string
+	key     string
+	action  string
+	changes string
+	actor   any
+}
+
+func auditTable(setting ConfigPgxAdapter) string {
+	if setting.AuditTable != "" {
+		return setting.AuditTable
+	}
+	if table := os.Getenv("ENV_DB_AUDIT_TABLE"); table != "" {
+		return table
+	}
+	return defaultAuditTable
+}
+
+/*
+auditEntries genera los registros de auditoría de una sentencia ejecutada.
+
+Los registros anteriores (previous) se relacionan con los devueltos por RETURNING (current) mediante la llave primaria.
+En un DELETE sin SELECT previo los registros devueltos son los eliminados, un UPSERT se registra como UPDATE si el registro existía.
+*/
+func (p PgxAdapter) auditEntries(ctx context.Context, item DataExec, previous, current []map[string]any) ([]auditEntry, error) {
+	if item.Action == DELETE && item.Audit.Query == "" {
+		previous, current = current, nil
+	}
+
+	before := make(map[string]map[string]any, len(previous))
+	for _, row := range previous {
+		before[auditKey(item.Audit.Keys, row)] = row
+	}
+
+	actor := ctx.Value(ActorId)
+	if actor != nil {
+		actor = fmt.Sprint(actor)
+	}
+
+	var entries []auditEntry
+	add := func(key string, old, new map[string]any) error {
+		changes, err := json.Marshal(auditChanges(old, new))
+		if err != nil {
+			return fmt.Errorf("no se pudo registrar la auditoría de %s (%s): %w", item.Table, key, err)
+		}
+		action := item.Action
+		if action == UPSERT {
+			// el upsert se registra como UPDATE si el registro existía y como INSERT si se creo
+			action = INSERT
+			if old != nil {
+				action = UPDATE
+			}
+		}
+		entries = append(entries, auditEntry{table: item.Table, key: key, action: action.String(), changes: string(changes), actor: actor})
+		return nil
+	}
+
+	for _, row := range current {
+		key := auditKey(item.Audit.Keys, row)
+		if err := add(key, before[key], row); err != nil {
+			return nil, err
+		}
+	}
+	if current == nil {
+		for _, row := range previous {
+			if err := add(auditKey(item.Audit.Keys, row), row, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return entries, nil
+}
+
+// auditKey describe el registro con los valores de su llave primaria (por ejemplo `id=10`)
+func auditKey(keys []string, row map[string]any) string {
+	values := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, fmt.Sprintf("%s=%v", k, row[k]))
+	}
+	return strings.Join(values, ", ")
+}
+
+// auditChanges devuelve los campos modificados con sus valores `old` y `new`, en un update solo se incluyen los campos que cambiaron
+func auditChanges(old, new map[string]any) map[string]map[string]any {
+	changes := make(map[string]map[string]any)
+	switch {
+	case old == nil:
+		for k, v := range new {
+			changes[k] = map[string]any{"new": v}
+		}
+	case new == nil:
+		for k, v := range old {
+			changes[k] = map[string]any{"old": v}
+		}
+	default:
+		for k, v := range new {
+			if fmt.Sprint(old[k]) != fmt.Sprint(v) {
+				changes[k] = map[string]any{"old": old[k], "new": v}
+			}
+		}
+	}
+	return changes
+}
+
+// insertAudit guarda los registros de auditoría dentro de la misma transacción, en sentencias de varios registros bajo el limite de parámetros
+func (p PgxAdapter) insertAudit(ctx context.Context, exec dbExecutor, entries []auditEntry) error {
+	table := p.audit
+	if table == "" {
+		table = defaultAuditTable
+	}
+
+	size := MaxParameters / len(auditColumns)
+	for start := 0; start < len(entries); start += size {
+		chunk := entries[start:min(start+size, len(entries))]
+		tuples := make([]string, 0, len(chunk))
+		values := make([]any, 0, len(chunk)*len(auditColumns))
+		for _, e := range chunk {
+			n := len(values)
+			tuples = append(tuples, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, now())", n+1, n+2, n+3, n+4, n+5))
+			values = append(values, e.table, e.key, e.action, e.changes, e.actor)
+		}
+		query := fmt.Sprintf("INSERT INTO %s (%s, created_at) VALUES %s", table, strings.Join(auditColumns, ", "), strings.Join(tuples, ", "))
+		if _, err := exec.Exec(ctx, query, values...); err != nil {
+			return fmt.Errorf("no se pudo registrar la auditoría: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAuditEntries_Upsert(t *testing.T) {
+	item := DataExec{Action: UPSERT, Table: "items", Audit: &AuditExec{Keys: []string{"id"}, Query: "SELECT"}}
+	previous := []map[string]any{{"id": 1, "total": 10}}
+	current := []map[string]any{{"id": 1, "total": 20}, {"id": 2, "total": 5}}
+	entries, err := PgxAdapter{}.auditEntries(context.Background(), item, previous, current)
+	if err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].action != "UPDATE" || entries[1].action != "INSERT" {
+		t.Errorf("acciones inesperadas: %+v", entries)
+	}
+	if entries[0].changes != `{"total":{"new":20,"old":10}}` {
+		t.Errorf("cambios inesperados: %s", entries[0].changes)
+	}
+}
@@ -2,7 +2,9 @@ package adapters
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
@@ -13,15 +15,114 @@ const (
 	INSERT
 	UPDATE
 	DELETE
+	UPSERT
 )
 
+func (a Actions) String() string {
+	switch a {
+	case INSERT:
+		return "INSERT"
+	case UPDATE:
+		return "UPDATE"
+	case DELETE:
+		return "DELETE"
+	case UPSERT:
+		return "UPSERT"
+	}
+	return "NONE"
+}
+
 type DataExec struct {
-	Querys string
-	Values []any
-	Action Actions
+	Querys    string
+	Values    []any
+	Action    Actions
+	Table     string     // tabla destino de la sentencia (y de COPY FROM)
+	Columns   []string   // columnas enviadas con COPY FROM
+	Rows      [][]any    // filas enviadas con COPY FROM, si existen se ignora Querys
+	Returning bool       // la sentencia tiene cláusula RETURNING y sus registros se leen como resultado
+	Version   bool       // la sentencia compara la versión del registro, si no afecta registros se retorna ErrStaleEntity
+	Key       string     // llave del registro afectado (por ejemplo `id=10`), usada en los mensajes de error
+	Count     int64      // registros que debe afectar una sentencia masiva con control de versión, si afecta menos se retorna ErrStaleEntity
+	Expect    bool       // la sentencia debe afectar al menos un registro, si no se retorna ErrRowsAffected
+	MaxRows   int64      // máximo de registros que puede afectar la sentencia (0 sin limite), si se supera se retorna ErrRowsAffected
+	Audit     *AuditExec // auditoría de la sentencia, nil si el esquema no es auditado
+	// AfterTx se ejecuta dentro de la transacción después de todas las sentencias y antes del commit, si retorna error se revierte
+	AfterTx func(ctx context.Context, tx pgx.Tx) error
+}
+
+// ResultExec guarda el resultado de una sentencia ejecutada, en el mismo orden que los DataExec enviados
+type ResultExec struct {
+	Action       Actions
+	Table        string
+	RowsAffected int64            // registros afectados según el CommandTag (o filas copiadas con COPY FROM)
+	Rows         []map[string]any // registros devueltos por RETURNING
+}
+
+// Results son los resultados de las sentencias de una transacción
+type Results []ResultExec
+
+// RowsAffected retorna el total de registros afectados por todas las sentencias
+func (r Results) RowsAffected() int64 {
+	var total int64
+	for _, v := range r {
+		total += v.RowsAffected
+	}
+	return total
+}
+
+// Rows retorna todos los registros devueltos por RETURNING
+func (r Results) Rows() []map[string]any {
+	var rows []map[string]any
+	for _, v := range r {
+		rows = append(rows, v.Rows...)
+	}
+	return rows
 }
 
 // dbExecutor es una interfaz interna para aceptar tanto conexiones como transacciones
 type dbExecutor interface {
 	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// StatementError indica la sentencia que fallo dentro de una transacción, Index es su posición en el pipeline
+type StatementError struct {
+	Index  int
+	Action Actions
+	Query  string
+	Err    error
+}
+
+func (e *StatementError) Error() string {
+	return fmt.Sprintf("fallo la sentencia %d: %s", e.Index, e.Err.Error())
+}
+
+func (e *StatementError) Unwrap() error {
+	return e.Err
+}
+
+// ErrRowsAffected indica que una sentencia no afecto registros o afecto mas de los esperados, la transacción se revierte
+type ErrRowsAffected struct {
+	Action       Actions
+	Table        string
+	RowsAffected int64
+	MaxRows      int64
+}
+
+func (e *ErrRowsAffected) Error() string {
+	if e.RowsAffected == 0 {
+		return fmt.Sprintf("la sentencia sobre la tabla %s no afecto ningún registro", e.Table)
+	}
+	return fmt.Sprintf("la sentencia sobre la tabla %s afecto %d registros, se esperaba como máximo %d", e.Table, e.RowsAffected, e.MaxRows)
+}
+
+// ErrStaleEntity indica que un update con control de versión no encontró el registro con la versión esperada
+type ErrStaleEntity struct {
+	Table string
+	Key   string
+}
+
+func (e *ErrStaleEntity) Error() string {
+	return fmt.Sprintf("el registro (%s) de la tabla %s fue modificado por otra transacción", e.Key, e.Table)
 }
@@ -2,6 +2,7 @@ package adapters
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -9,7 +10,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/deybin/pgorm/internal/utils"
+	"github.com/deybin/pgorm/logger"
 	"github.com/georgysavva/scany/v2/pgxscan"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -28,6 +29,7 @@ type PgxAdapter struct {
 	port     string
 	ssl      string //disable,require
 	appName  string
+	audit    string //tabla donde se registra la auditoría de los esquemas auditados
 }
 
 type ConfigPgxAdapter struct {
@@ -42,6 +44,7 @@ type ConfigPgxAdapter struct {
 	MaxConns        int32
 	MinConns        int32
 	MaxConnIdleTime time.Duration
+	AuditTable      string //tabla de auditoría, por defecto audit_log
 }
 
 type contextSchema string
@@ -162,6 +165,7 @@ func NewPool(setting ConfigPgxAdapter) (*PgxAdapter, error) {
 		ssl:      ssl,
 		port:     port,
 		appName:  appName,
+		audit:    auditTable(setting),
 	}, nil
 
 }
@@ -294,6 +298,7 @@ func NewPoolWithConfig(setting ConfigPgxAdapter) (*PgxAdapter, error) {
 		ssl:      ssl,
 		port:     port,
 		appName:  appName,
+		audit:    auditTable(setting),
 	}, nil
 
 }
@@ -319,22 +324,204 @@ func (p PgxAdapter) setSchema(ctx context.Context, conn *pgxpool.Conn, schema an
 	return nil
 }
 
-func (p PgxAdapter) executeInternal(ctx context.Context, exec dbExecutor, data ...DataExec) error {
-	cross := false // O tu lógica de negocio para cross
-	for _, item := range data {
-		sqlPre := item.Querys
-		if cross && item.Action == UPDATE {
-			sqlPre = utils.QueryCrossUpdate(sqlPre)
+/*
+executeInternal envía las sentencias de una transacción en un solo pipeline (pgx.Batch).
+
+Las sentencias se encolan en orden y se envían juntas; las filas destinadas a COPY FROM cortan el pipeline:
+se envía lo encolado hasta ese momento, se ejecuta el COPY y se continúa con las siguientes sentencias.
+Las sentencias con RETURNING se leen como consulta y sus registros se guardan en el resultado.
+
+Las sentencias auditadas encolan antes su SELECT previo; los registros de auditoría se insertan al final, en el mismo ejecutor (transacción).
+
+Si una sentencia falla se retorna un *StatementError con su posición dentro de data.
+*/
+func (p PgxAdapter) executeInternal(ctx context.Context, exec dbExecutor, data ...DataExec) ([]ResultExec, error) {
+	results := make([]ResultExec, len(data))
+	for i, item := range data {
+		results[i] = ResultExec{Action: item.Action, Table: item.Table}
+	}
+	batch := &pgx.Batch{}
+	var queued []int
+	var audits []auditEntry
+
+	flush := func() error {
+		if batch.Len() == 0 {
+			return nil
 		}
+		br := exec.SendBatch(ctx, batch)
+		for _, index := range queued {
+			item := data[index]
+			var previous []map[string]any
+			if item.Audit != nil && item.Audit.Query != "" {
+				rows, err := p.returningRows(br)
+				if err != nil {
+					br.Close()
+					return p.statementError(ctx, index, item, err)
+				}
+				previous = rows
+			}
+
+			var affected int64
+			if item.Returning {
+				rows, err := p.returningRows(br)
+				if err != nil {
+					br.Close()
+					return p.statementError(ctx, index, item, err)
+				}
+				results[index].Rows = rows
+				affected = int64(len(rows))
+			} else {
+				tag, err := br.Exec()
+				if err != nil {
+					br.Close()
+					return p.statementError(ctx, index, item, err)
+				}
+				affected = tag.RowsAffected()
+			}
+			results[index].RowsAffected = affected
+			if err := p.checkAffected(item, affected); err != nil {
+				br.Close()
+				return p.statementError(ctx, index, item, err)
+			}
 
-		if _, err := exec.Exec(ctx, sqlPre, item.Values...); err != nil {
-			slog.Error("Fallo Exec", "error", err)
-			return err
+			if item.Audit != nil {
+				entries, err := p.auditEntries(ctx, item, previous, results[index].Rows)
+				if err != nil {
+					br.Close()
+					return p.statementError(ctx, index, item, err)
+				}
+				audits = append(audits, entries...)
+			}
 		}
+		batch = &pgx.Batch{}
+		queued = nil
+		return br.Close()
+	}
+
+	for i, item := range data {
+		if len(item.Rows) > 0 {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			affected, err := exec.CopyFrom(ctx, pgx.Identifier(strings.Split(item.Table, ".")), item.Columns, pgx.CopyFromRows(item.Rows))
+			if err != nil {
+				return nil, p.statementError(ctx, i, item, err)
+			}
+			results[i].RowsAffected = affected
+			continue
+		}
+
+		if item.Audit != nil && item.Audit.Query != "" {
+			batch.Queue(item.Audit.Query, item.Audit.Values...)
+		}
+		batch.Queue(item.Querys, item.Values...)
+		queued = append(queued, i)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	if len(audits) > 0 {
+		if err := p.insertAudit(ctx, exec, audits); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// returningRows lee los registros devueltos por la siguiente sentencia del pipeline
+func (p PgxAdapter) returningRows(br pgx.BatchResults) ([]map[string]any, error) {
+	rows, err := br.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols := p.keyFieldName(rows)
+	fieldDescs := rows.FieldDescriptions()
+	result := make([]map[string]any, 0)
+	for rows.Next() {
+		row, err := p.builderResult(cols, rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p.normalizeRow(row, fieldDescs))
+	}
+	return result, rows.Err()
+}
+
+// checkAffected valida los registros afectados por la sentencia contra el control de versión y los limites esperados
+func (p PgxAdapter) checkAffected(item DataExec, affected int64) error {
+	if item.Version && (affected == 0 || affected < item.Count) {
+		return &ErrStaleEntity{Table: item.Table, Key: item.Key}
+	}
+	if (item.Expect && affected == 0) || (item.MaxRows > 0 && affected > item.MaxRows) {
+		return &ErrRowsAffected{Action: item.Action, Table: item.Table, RowsAffected: affected, MaxRows: item.MaxRows}
 	}
 	return nil
 }
 
+// statementError describe la sentencia que fallo, los errores de PostgreSQL se traducen al idioma del contexto
+func (p PgxAdapter) statementError(ctx context.Context, index int, item DataExec, err error) error {
+	slog.Error("Fallo Exec", "statement", index, "error", err)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		err = logger.ManagerErrorsContext(ctx).SqlQuery(err)
+	}
+	query := item.Querys
+	if len(item.Rows) > 0 {
+		query = fmt.Sprintf("COPY %s (%s)", item.Table, strings.Join(item.Columns, ", "))
+	}
+	return &StatementError{Index: index, Action: item.Action, Query: query, Err: err}
+}
+
+/*
+executeTransaction ejecuta los grupos de sentencias dentro de una única transacción.
+
+Todas las sentencias de todos los grupos se envían en el mismo pipeline, si alguna falla se revierte la transacción.
+Los resultados se devuelven agrupados igual que las sentencias recibidas.
+*/
+func (p PgxAdapter) executeTransaction(ctx context.Context, conn *pgxpool.Conn, groups ...[]DataExec) ([][]ResultExec, error) {
+	var data []DataExec
+	for _, group := range groups {
+		data = append(data, group...)
+	}
+
+	// Iniciar transacción
+	tx, err := conn.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Pasamos 'tx' como el ejecutor. Si falla, el motor devuelve error.
+	results, err := p.executeInternal(ctx, tx, data...)
+	if err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+
+	for i, item := range data {
+		if item.AfterTx == nil {
+			continue
+		}
+		if err := item.AfterTx(ctx, tx); err != nil {
+			tx.Rollback(ctx)
+			return nil, p.statementError(ctx, i, item, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	grouped := make([][]ResultExec, len(groups))
+	start := 0
+	for i, group := range groups {
+		grouped[i] = results[start : start+len(group)]
+		start += len(group)
+	}
+	return grouped, nil
+}
+
 /*
 keyFieldName extrae los nombres de las columnas devueltos por la consulta ejecutada y los almacena en `q.colSql`.
 
@@ -383,7 +570,7 @@ func (p PgxAdapter) Execute(ctx context.Context, sql string, args ...any) ([]map
 	conn, err := p.db.Acquire(ctx)
 	if err != nil {
 		slog.Error("Fallo conexión db", "error", err)
-		return []map[string]any{}, err
+		return []map[string]any{}, logger.ManagerErrorsContext(ctx).SqlConnections(err)
 	}
 
 	defer func() {
@@ -392,15 +579,13 @@ func (p PgxAdapter) Execute(ctx context.Context, sql string, args ...any) ([]map
 		conn.Release() // Devuelve al pool
 	}()
 
-	schema := ctx.Value(SchemaId)
-
-	if err := p.setSchema(ctx, conn, schema.(string)); err != nil {
+	if err := p.setSchema(ctx, conn, ctx.Value(SchemaId)); err != nil {
 		return []map[string]any{}, err
 	}
 
 	rows, err := conn.Query(ctx, sql, args...)
 	if err != nil {
-		return []map[string]any{}, err
+		return []map[string]any{}, logger.ManagerErrorsContext(ctx).SqlQuery(err)
 	}
 
 	defer rows.Close()
@@ -425,7 +610,7 @@ func (p PgxAdapter) ExecuteWithPgxScan(ctx context.Context, dest any, sql string
 	conn, err := p.db.Acquire(ctx)
 	if err != nil {
 		slog.Error("Fallo conexión db", "error", err)
-		return err
+		return logger.ManagerErrorsContext(ctx).SqlConnections(err)
 	}
 	defer func() {
 		// conn.Exec(ctx, "DISCARD ALL") // Limpia el estado
@@ -440,7 +625,7 @@ func (p PgxAdapter) ExecuteWithPgxScan(ctx context.Context, dest any, sql string
 	rows, err := conn.Query(ctx, sql, args...)
 	if err != nil {
 		slog.Error("Fallo al ejecutar", "error", err)
-		return err
+		return logger.ManagerErrorsContext(ctx).SqlQuery(err)
 	}
 	defer rows.Close()
 
@@ -466,7 +651,7 @@ func (p PgxAdapter) ExecuteWithPgxScanAndSchema(schema string, ctx context.Conte
 	conn, err := p.db.Acquire(ctx)
 	if err != nil {
 		slog.Error("Fallo conexión db", "error", err)
-		return err
+		return logger.ManagerErrorsContext(ctx).SqlConnections(err)
 	}
 	defer func() {
 		conn.Exec(ctx, "SET search_path TO DEFAULT")
@@ -481,7 +666,7 @@ func (p PgxAdapter) ExecuteWithPgxScanAndSchema(schema string, ctx context.Conte
 	rows, err := conn.Query(ctx, sql, args...)
 	if err != nil {
 		slog.Error("Fallo al ejecutar", "error", err)
-		return err
+		return logger.ManagerErrorsContext(ctx).SqlQuery(err)
 	}
 	defer rows.Close()
 
@@ -529,7 +714,7 @@ func (p *PgxAdapter) Procedure(ctx context.Context, sql string, arguments ...any
 	conn, err := p.db.Acquire(ctx)
 	if err != nil {
 		slog.Error("Fallo conexión db", "error", err)
-		return err
+		return logger.ManagerErrorsContext(ctx).SqlConnections(err)
 	}
 	defer func() {
 		conn.Exec(ctx, "SET search_path TO DEFAULT")
@@ -543,7 +728,7 @@ func (p *PgxAdapter) Procedure(ctx context.Context, sql string, arguments ...any
 
 	if _, err := conn.Exec(ctx, sql, arguments...); err != nil {
 		slog.Error("Fallo Exec", "error", err)
-		return err
+		return logger.ManagerErrorsContext(ctx).SqlQuery(err)
 	}
 	return nil
 
@@ -553,7 +738,7 @@ func (p *PgxAdapter) ProcedureWithSchema(schema string, ctx context.Context, sql
 	conn, err := p.db.Acquire(ctx)
 	if err != nil {
 		slog.Error("Fallo conexión db", "error", err)
-		return err
+		return logger.ManagerErrorsContext(ctx).SqlConnections(err)
 	}
 	defer func() {
 		conn.Exec(ctx, "SET search_path TO DEFAULT")
@@ -567,7 +752,7 @@ func (p *PgxAdapter) ProcedureWithSchema(schema string, ctx context.Context, sql
 
 	if _, err := conn.Exec(ctx, sql, arguments...); err != nil {
 		slog.Error("Fallo Exec", "error", err)
-		return err
+		return logger.ManagerErrorsContext(ctx).SqlQuery(err)
 	}
 	return nil
 
@@ -670,11 +855,11 @@ func (p PgxAdapter) normalizeRow(row map[string]interface{}, fieldDescs []pgconn
 	return row
 }
 
-func (p PgxAdapter) ExecuteTransactions(ctx context.Context, dataExec ...DataExec) error {
+func (p PgxAdapter) ExecuteTransactions(ctx context.Context, dataExec ...DataExec) ([]ResultExec, error) {
 	conn, err := p.db.Acquire(ctx)
 	if err != nil {
 		slog.Error("Fallo conexión db", "error", err)
-		return err
+		return nil, logger.ManagerErrorsContext(ctx).SqlConnections(err)
 	}
 	defer func() {
 		conn.Exec(ctx, "SET search_path TO DEFAULT")
@@ -683,17 +868,21 @@ func (p PgxAdapter) ExecuteTransactions(ctx context.Context, dataExec ...DataExe
 	}()
 
 	if err := p.setSchema(ctx, conn, ctx.Value(SchemaId)); err != nil {
-		return err
+		return nil, err
 	}
 
-	return p.executeInternal(ctx, conn, dataExec...)
+	results, err := p.executeTransaction(ctx, conn, dataExec)
+	if err != nil {
+		return nil, err
+	}
+	return results[0], nil
 }
 
-func (p PgxAdapter) ExecuteTransactionsWithSchema(schema string, ctx context.Context, dataExec ...DataExec) error {
+func (p PgxAdapter) ExecuteTransactionsWithSchema(schema string, ctx context.Context, dataExec ...DataExec) ([]ResultExec, error) {
 	conn, err := p.db.Acquire(ctx)
 	if err != nil {
 		slog.Error("Fallo conexión db", "error", err)
-		return err
+		return nil, logger.ManagerErrorsContext(ctx).SqlConnections(err)
 	}
 	defer func() {
 		conn.Exec(ctx, "SET search_path TO DEFAULT")
@@ -702,18 +891,22 @@ func (p PgxAdapter) ExecuteTransactionsWithSchema(schema string, ctx context.Con
 	}()
 
 	if err := p.setSchema(ctx, conn, schema); err != nil {
-		return err
+		return nil, err
 	}
 
-	return p.executeInternal(ctx, conn, dataExec...)
+	results, err := p.executeTransaction(ctx, conn, dataExec)
+	if err != nil {
+		return nil, err
+	}
+	return results[0], nil
 }
 
-func (p PgxAdapter) ExecuteTransactionsMulti(ctx context.Context, dataExec ...[]DataExec) error {
+func (p PgxAdapter) ExecuteTransactionsMulti(ctx context.Context, dataExec ...[]DataExec) ([][]ResultExec, error) {
 
 	conn, err := p.db.Acquire(ctx)
 	if err != nil {
 		slog.Error("Fallo conexión db", "error", err)
-		return err
+		return nil, logger.ManagerErrorsContext(ctx).SqlConnections(err)
 	}
 	defer func() {
 		conn.Exec(ctx, "SET search_path TO DEFAULT")
@@ -722,32 +915,17 @@ func (p PgxAdapter) ExecuteTransactionsMulti(ctx context.Context, dataExec ...[]
 	}()
 
 	if err := p.setSchema(ctx, conn, ctx.Value(SchemaId)); err != nil {
-		return err
-	}
-	// Iniciar transacción
-	tx, err := conn.BeginTx(ctx, pgx.TxOptions{})
-	if err != nil {
-		return err
-	}
-
-	// Ejecutar cada grupo de queries usando el motor interno
-	for _, group := range dataExec {
-		// Pasamos 'tx' como el ejecutor. Si falla, el motor devuelve error.
-		if err := p.executeInternal(ctx, tx, group...); err != nil {
-			tx.Rollback(ctx)
-			return err
-		}
+		return nil, err
 	}
-
-	return tx.Commit(ctx)
+	return p.executeTransaction(ctx, conn, dataExec...)
 }
 
-func (p PgxAdapter) ExecuteTransactionsMultiWithSchema(schema string, ctx context.Context, dataExec ...[]DataExec) error {
+func (p PgxAdapter) ExecuteTransactionsMultiWithSchema(schema string, ctx context.Context, dataExec ...[]DataExec) ([][]ResultExec, error) {
 
 	conn, err := p.db.Acquire(ctx)
 	if err != nil {
 		slog.Error("Fallo conexión db", "error", err)
-		return err
+		return nil, logger.ManagerErrorsContext(ctx).SqlConnections(err)
 	}
 	defer func() {
 		conn.Exec(ctx, "SET search_path TO DEFAULT")
@@ -757,22 +935,7 @@ func (p PgxAdapter) ExecuteTransactionsMultiWithSchema(schema string, ctx contex
 
 	// Configurar el esquema para ESTA sesión
 	if err := p.setSchema(ctx, conn, schema); err != nil {
-		return err
-	}
-	// Iniciar transacción
-	tx, err := conn.BeginTx(ctx, pgx.TxOptions{})
-	if err != nil {
-		return err
-	}
-
-	// Ejecutar cada grupo de queries usando el motor interno
-	for _, group := range dataExec {
-		// Pasamos 'tx' como el ejecutor. Si falla, el motor devuelve error.
-		if err := p.executeInternal(ctx, tx, group...); err != nil {
-			tx.Rollback(ctx)
-			return err
-		}
+		return nil, err
 	}
-
-	return tx.Commit(ctx)
+	return p.executeTransaction(ctx, conn, dataExec...)
 }
@@ -0,0 +1,122 @@
+package builder
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/deybin/pgorm/internal/adapters"
+	"github.com/deybin/pgorm/internal/core/domain"
+	"github.com/deybin/pgorm/migrator"
+)
+
+// audited indica si el esquema de la transacción registra auditoría
+func audited(ts *domain.Transactions) bool {
+	return migrator.IsAudited(ts.Schema())
+}
+
+// checkAudit rechaza la auditoría de esquemas sin llave primaria, sin ella no se puede identificar el registro auditado
+func checkAudit(ts *domain.Transactions) error {
+	if !audited(ts) {
+		return nil
+	}
+	for _, f := range ts.Schema().ParseInsert() {
+		if f.PrimaryKey {
+			return nil
+		}
+	}
+	return fmt.Errorf("la tabla %s es auditada y no tiene llave primaria (primaryKey)", ts.Schema().Table().Name())
+}
+
+/*
+auditExec genera la configuración de auditoría de una sentencia, nil si el esquema no es auditado.
+
+	Parámetros
+		* ts {*domain.Transactions}: transacción de la sentencia
+		* schemas {[]migrator.Fields}: campos del esquema, de ellos se toma la llave primaria
+		* where {[]migrator.Where}: condiciones ya validadas de la sentencia
+		* previous {bool}: si se deben leer los registros antes de modificarlos (update, eliminación lógica)
+*/
+func auditExec(ts *domain.Transactions, schemas []migrator.Fields, where []migrator.Where, previous bool) *adapters.AuditExec {
+	if !audited(ts) {
+		return nil
+	}
+	audit := &adapters.AuditExec{}
+	for _, f := range schemas {
+		if f.PrimaryKey {
+			audit.Keys = append(audit.Keys, f.Name)
+		}
+	}
+	if previous {
+		var i int
+		conditions, values := whereConditions(where, &i)
+		query := "SELECT * FROM " + ts.Schema().Table().Name()
+		if clause := whereClause(conditions, nil); clause != "" {
+			query += " " + clause
+		}
+		audit.Query = query + " FOR UPDATE"
+		audit.Values = values
+	}
+	return audit
+}
+
+/*
+auditUpsertExec genera la auditoría de un upsert, si el conflicto actualiza el registro el SELECT previo lee sus valores
+anteriores con las columnas del conflicto (la llave primaria si el conflicto es una restricción por nombre).
+
+	Parámetros
+		* target {[]string}: columnas del conflicto
+		* row {map[string]any}: valores insertados
+		* update {bool}: el conflicto actualiza el registro (DO UPDATE)
+*/
+func auditUpsertExec(ts *domain.Transactions, schemas []migrator.Fields, target []string, row map[string]any, update bool) *adapters.AuditExec {
+	audit := auditExec(ts, schemas, nil, false)
+	if audit == nil || !update {
+		return audit
+	}
+	if len(target) == 0 {
+		target = audit.Keys
+	}
+	conditions := make([]string, 0, len(target))
+	for i, column := range target {
+		value, ok := row[column]
+		if !ok {
+			return audit
+		}
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", column, i+1))
+		audit.Values = append(audit.Values, value)
+	}
+	audit.Query = fmt.Sprintf("SELECT * FROM %s WHERE %s FOR UPDATE", ts.Schema().Table().Name(), strings.Join(conditions, " AND "))
+	return audit
+}
+
+// auditKeysExec genera la auditoría de una sentencia masiva, el SELECT previo busca los registros por su llave primaria
+// (con una sola columna se envía un arreglo con `= ANY($1)` para no depender del limite de parámetros)
+func auditKeysExec(ts *domain.Transactions, primaryKeys []string, keys [][]any) *adapters.AuditExec {
+	if !audited(ts) {
+		return nil
+	}
+	table := ts.Schema().Table().Name()
+	if len(primaryKeys) == 1 {
+		return &adapters.AuditExec{
+			Keys:   primaryKeys,
+			Query:  fmt.Sprintf("SELECT * FROM %s WHERE %s = ANY($1) FOR UPDATE", table, primaryKeys[0]),
+			Values: []any{keyList(keys)},
+		}
+	}
+	tuples, values := valuesRows(keys, 0)
+	return &adapters.AuditExec{
+		Keys:   primaryKeys,
+		Query:  fmt.Sprintf("SELECT * FROM %s WHERE (%s) IN (%s) FOR UPDATE", table, strings.Join(primaryKeys, ", "), tuples),
+		Values: values,
+	}
+}
+
+// keyList convierte las llaves de una sola columna en un slice tipado ([]string, []int64, ...) para enviarlo como arreglo
+func keyList(keys [][]any) any {
+	list := reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(keys[0][0])), 0, len(keys))
+	for _, row := range keys {
+		list = reflect.Append(list, reflect.ValueOf(row[0]))
+	}
+	return list.Interface()
+}
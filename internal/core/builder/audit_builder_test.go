@@ -0,0 +1,147 @@
+package builder_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deybin/pgorm/internal/adapters"
+	"github.com/deybin/pgorm/internal/core/builder"
+	"github.com/deybin/pgorm/migrator"
+)
+
+type auditItem struct {
+	Id    string  `validate:"primaryKey;required"`
+	Total float64 `validate:"required;update"`
+}
+
+func (e auditItem) Name() string      { return "audit_items" }
+func (e auditItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e auditItem) Values() []any     { return migrator.EntityValues(e) }
+
+type auditSchema struct {
+	schema[auditItem]
+}
+
+func (s auditSchema) Audit() bool {
+	return true
+}
+
+func TestAudit_Update(t *testing.T) {
+	ts := transaction(auditSchema{}, adapters.UPDATE, migrator.EntityUpdate{Entity: auditItem{Total: 150}, Conditions: whereId("1")})
+	if err := builder.BuilderUpdateGeneric(ts); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	query := ts.Query()[0]
+	if query.Querys != "UPDATE audit_items SET total= $1 WHERE id = $2 RETURNING *" || !query.Returning {
+		t.Errorf("un esquema auditado debe retornar los registros: %q", query.Querys)
+	}
+	audit := query.Audit
+	if audit == nil || audit.Query != "SELECT * FROM audit_items WHERE id = $1 FOR UPDATE" || len(audit.Keys) != 1 || audit.Values[0] != "1" {
+		t.Errorf("auditoría inesperada: %+v", audit)
+	}
+}
+
+func TestAudit_BulkInsert(t *testing.T) {
+	ts := transaction(auditSchema{}, adapters.INSERT, auditItem{Id: "1", Total: 10}, auditItem{Id: "2", Total: 20})
+	if err := builder.BuilderBulkInsertGeneric(ts); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	query := ts.Query()[0]
+	if len(query.Rows) != 0 || !query.Returning || query.Audit == nil || query.Audit.Query != "" {
+		t.Errorf("un esquema auditado no debe usar COPY: %+v", query)
+	}
+
+	if migrator.IsAudited(schema[auditItem]{}) {
+		t.Errorf("el esquema sin Audit no es auditado")
+	}
+}
+
+type auditNoKeyItem struct {
+	Code  string  `validate:"required;where"`
+	Total float64 `validate:"required;update"`
+}
+
+func (e auditNoKeyItem) Name() string      { return "audit_no_key_items" }
+func (e auditNoKeyItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e auditNoKeyItem) Values() []any     { return migrator.EntityValues(e) }
+
+type auditNoKeySchema struct {
+	schema[auditNoKeyItem]
+}
+
+func (s auditNoKeySchema) Audit() bool {
+	return true
+}
+
+func TestAudit_RequiresPrimaryKey(t *testing.T) {
+	ts := transaction(auditNoKeySchema{}, adapters.INSERT, auditNoKeyItem{Code: "a", Total: 10})
+	if err := builder.BuilderInsertGeneric(ts); err == nil || len(ts.Query()) != 0 {
+		t.Errorf("un esquema auditado sin llave primaria debe rechazarse: %v", err)
+	}
+}
+
+func TestAudit_Upsert(t *testing.T) {
+	ts := transaction(auditSchema{}, adapters.UPSERT, auditItem{Id: "1", Total: 10})
+	if err := builder.BuilderUpsertGeneric(ts, migrator.UpsertOptions{}); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	audit := ts.Query()[0].Audit
+	if audit == nil || audit.Query != "SELECT * FROM audit_items WHERE id = $1 FOR UPDATE" || len(audit.Values) != 1 || audit.Values[0] != "1" {
+		t.Errorf("el upsert debe leer el registro anterior: %+v", audit)
+	}
+
+	ts = transaction(auditSchema{}, adapters.UPSERT, auditItem{Id: "1", Total: 10})
+	if err := builder.BuilderUpsertGeneric(ts, migrator.UpsertOptions{DoNothing: true}); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	if audit := ts.Query()[0].Audit; audit == nil || audit.Query != "" {
+		t.Errorf("DO NOTHING no necesita el registro anterior: %+v", audit)
+	}
+}
+
+type auditSoftItem struct {
+	Id         int64      `validate:"primaryKey"`
+	Deleted_at *time.Time `validate:"softDelete"`
+}
+
+func (e auditSoftItem) Name() string      { return "audit_soft_items" }
+func (e auditSoftItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e auditSoftItem) Values() []any     { return migrator.EntityValues(e) }
+
+type auditSoftSchema struct {
+	schema[auditSoftItem]
+}
+
+func (s auditSoftSchema) Audit() bool {
+	return true
+}
+
+func TestAudit_BulkDeleteManyKeys(t *testing.T) {
+	items := make([]migrator.Entity, 70000)
+	for i := range items {
+		items[i] = auditSoftItem{Id: int64(i + 1)}
+	}
+	ts := transaction(auditSoftSchema{}, adapters.DELETE, items...)
+	if err := builder.BuilderBulkDeleteGeneric(ts); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	query := ts.Query()
+	if len(query) != 1 || query[0].Audit == nil {
+		t.Fatalf("query inesperado: %d sentencias", len(query))
+	}
+	audit := query[0].Audit
+	if audit.Query != "SELECT * FROM audit_soft_items WHERE id = ANY($1) FOR UPDATE" || len(audit.Values) != 1 {
+		t.Errorf("el SELECT previo debe enviar las llaves como arreglo: %q (%d valores)", audit.Query, len(audit.Values))
+	}
+	if ids, ok := audit.Values[0].([]int64); !ok || len(ids) != len(items) {
+		t.Errorf("se esperaba un arreglo tipado con todas las llaves: %T", audit.Values[0])
+	}
+
+	ts = transaction(auditSchema{}, adapters.DELETE, auditItem{Id: "1"}, auditItem{Id: "2"})
+	if err := builder.BuilderBulkDeleteGeneric(ts); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	if audit := ts.Query()[0].Audit; audit == nil || audit.Query != "" || len(audit.Keys) != 1 {
+		t.Errorf("la eliminación física no necesita el SELECT previo: %+v", audit)
+	}
+}
@@ -0,0 +1,35 @@
+package builder_test
+
+import (
+	"github.com/deybin/pgorm/internal/adapters"
+	"github.com/deybin/pgorm/internal/core/domain"
+	"github.com/deybin/pgorm/migrator"
+)
+
+// schema es el esquema de las entidades de prueba, cada test declara su propia entidad
+type schema[T migrator.Entity] struct {
+	table T
+}
+
+func (s schema[T]) Table() migrator.Entity {
+	return s.table
+}
+
+func (s schema[T]) ParseInsert() []migrator.Fields {
+	return migrator.GenerateSchema(s.table, migrator.INSERT)
+}
+
+func (s schema[T]) ParseUpdate() []migrator.Fields {
+	return migrator.GenerateSchema(s.table, migrator.UPDATE)
+}
+
+func (s schema[T]) ParseDelete() []migrator.Fields {
+	return migrator.GenerateSchema(s.table, migrator.DELETE)
+}
+
+// transaction crea la transacción de la acción indicada sobre el esquema s
+func transaction(s migrator.Schema, action adapters.Actions, datos ...migrator.Entity) *domain.Transactions {
+	ts := domain.NewTransaction(s, datos...)
+	ts.SetAction(action)
+	return &ts
+}
@@ -0,0 +1,103 @@
+package builder
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/deybin/pgorm/internal/adapters"
+	"github.com/deybin/pgorm/internal/core/domain"
+	"github.com/deybin/pgorm/migrator"
+)
+
+/*
+BuilderBulkDeleteGeneric genera la eliminación de todas las entidades de la transacción por su llave primaria.
+
+Con una llave primaria simple se envía una sola sentencia `= ANY($1)` con un arreglo del tipo del campo,
+con una llave compuesta se usa `(a, b) IN (($1, $2), ...)` dividida para no superar el limite de parámetros.
+Si el esquema usa eliminación lógica (softDelete) se genera un UPDATE que establece la fecha de eliminación.
+*/
+func BuilderBulkDeleteGeneric(ts *domain.Transactions) error {
+	if err := checkAudit(ts); err != nil {
+		return err
+	}
+	table := ts.Schema().Table().Name()
+	data := ts.Datos()
+	schemas := ts.Schema().ParseDelete()
+	if len(data) == 0 {
+		return messageError(ts, "data.delete")
+	}
+
+	var primaryKeys []migrator.Fields
+	var columns []string
+	for _, f := range schemas {
+		if f.PrimaryKey {
+			primaryKeys = append(primaryKeys, f)
+			columns = append(columns, f.Name)
+		}
+	}
+	if len(primaryKeys) == 0 {
+		return fmt.Errorf("la tabla %s no tiene llave primaria para eliminar de forma masiva", table)
+	}
+
+	keys := make([][]any, 0, len(data))
+	for _, item := range data {
+		v := reflect.Indirect(reflect.ValueOf(item))
+		row := make([]any, 0, len(primaryKeys))
+		var conditions []migrator.Where
+		for _, f := range primaryKeys {
+			value := f.FieldValue(v)
+			if !value.IsValid() || value.IsZero() {
+				return messageError(ts, "validate.required", f.Description)
+			}
+			row = append(row, value.Interface())
+			conditions = append(conditions, migrator.Where{Clause: "AND", Condition: "=", Field: f.Name, Value: value.Interface()})
+		}
+		if _, err := migrator.CheckWhereGenericContext(ts.Context(), schemas, conditions...); err != nil {
+			return err
+		}
+		keys = append(keys, row)
+	}
+
+	softDelete, soft := migrator.SoftDeleteField(schemas)
+	expect, maxRows := ts.Expect()
+	var sqlExec []adapters.DataExec
+	statement := func(conditions string, values []any, keys [][]any) {
+		var sqlPreparate string
+		if soft {
+			values = append(values, softDelete.TimeValue())
+			sqlPreparate = fmt.Sprintf("UPDATE %s SET %s= $%d WHERE %s AND %s IS NULL%s", table, softDelete.Name, len(values), conditions, softDelete.Name, returning(ts))
+		} else {
+			sqlPreparate = fmt.Sprintf("DELETE FROM %s WHERE %s%s", table, conditions, returning(ts))
+		}
+		// en la eliminación física los registros eliminados se leen con RETURNING, no se necesita el SELECT previo
+		audit := auditExec(ts, schemas, nil, false)
+		if soft {
+			audit = auditKeysExec(ts, columns, keys)
+		}
+		sqlExec = append(sqlExec, adapters.DataExec{
+			Querys:    sqlPreparate,
+			Values:    values,
+			Action:    ts.Action(),
+			Table:     table,
+			Returning: isReturning(ts),
+			Expect:    expect,
+			MaxRows:   maxRows,
+			Audit:     audit,
+		})
+	}
+
+	if len(primaryKeys) == 1 {
+		statement(fmt.Sprintf("%s = ANY($1)", columns[0]), []any{keyList(keys)}, keys)
+	} else {
+		size := (maxParameters - 1) / len(columns)
+		for start := 0; start < len(keys); start += size {
+			end := min(start+size, len(keys))
+			tuples, values := valuesRows(keys[start:end], 0)
+			statement(fmt.Sprintf("(%s) IN (%s)", strings.Join(columns, ", "), tuples), values, keys[start:end])
+		}
+	}
+
+	ts.SetQuery(sqlExec)
+	return nil
+}
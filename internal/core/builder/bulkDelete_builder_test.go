@@ -0,0 +1,38 @@
+package builder_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deybin/pgorm/internal/adapters"
+	"github.com/deybin/pgorm/internal/core/builder"
+	"github.com/deybin/pgorm/migrator"
+)
+
+type bulkSoft struct {
+	Id         string `validate:"primaryKey"`
+	Nombre     string
+	Deleted_at *time.Time `validate:"softDelete"`
+}
+
+func (e bulkSoft) Name() string      { return "bulk_soft" }
+func (e bulkSoft) Columns() []string { return migrator.EntityColumns(e) }
+func (e bulkSoft) Values() []any     { return migrator.EntityValues(e) }
+
+func TestBulkDelete_ByPrimaryKey(t *testing.T) {
+	ts := transaction(schema[bulkSoft]{}, adapters.DELETE, bulkSoft{Id: "1"}, &bulkSoft{Id: "2"})
+	if err := builder.BuilderBulkDeleteGeneric(ts); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	query := ts.Query()
+	if len(query) != 1 || query[0].Querys != "UPDATE bulk_soft SET deleted_at= $2 WHERE id = ANY($1) AND deleted_at IS NULL" {
+		t.Fatalf("query inesperado: %+v", query)
+	}
+	if ids, ok := query[0].Values[0].([]string); !ok || len(ids) != 2 {
+		t.Errorf("se esperaba un arreglo de llaves tipado: %v", query[0].Values[0])
+	}
+
+	if err := builder.BuilderBulkDeleteGeneric(transaction(schema[bulkSoft]{}, adapters.DELETE, bulkSoft{Nombre: "sin llave"})); err == nil {
+		t.Errorf("se esperaba un error por llave primaria vacía")
+	}
+}
@@ -0,0 +1,89 @@
+package builder
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/deybin/pgorm/internal/adapters"
+	"github.com/deybin/pgorm/internal/core/domain"
+	"github.com/deybin/pgorm/migrator"
+)
+
+func BuilderBulkInsertGeneric(ts *domain.Transactions) error {
+	if err := checkAudit(ts); err != nil {
+		return err
+	}
+	table := ts.Schema().Table().Name()
+	data := ts.Datos()
+	schema := ts.Schema().ParseInsert()
+	length := len(data)
+	if length > 0 {
+		var sqlExec = make([]adapters.DataExec, 0)
+		var data_insert []map[string]any
+		// los registros que tienen las mismas columnas se agrupan en un solo COPY, así los campos omitidos conservan su DEFAULT
+		groups := make(map[string]int)
+
+		for n, item := range data {
+			preArray, err := migrator.CheckInsertGenericContext(ts.Context(), schema, item)
+			if err != nil {
+				return err
+			}
+			// COPY e INSERT necesitan al menos una columna, un registro sin valores no se puede enviar en el lote
+			if len(preArray) == 0 {
+				return messageError(ts, "data.insert.columns", n+1)
+			}
+			data_insert = append(data_insert, preArray)
+
+			column := slices.Sorted(maps.Keys(preArray))
+			row := make([]any, len(column))
+			for i, k := range column {
+				row[i] = preArray[k]
+			}
+
+			key := strings.Join(column, ",")
+			position, ok := groups[key]
+			if !ok {
+				sqlExec = append(sqlExec, adapters.DataExec{
+					Table:   table,
+					Columns: column,
+					Action:  ts.Action(),
+				})
+				position = len(sqlExec) - 1
+				groups[key] = position
+			}
+			sqlExec[position].Rows = append(sqlExec[position].Rows, row)
+		}
+
+		// COPY FROM no devuelve registros, si se solicito RETURNING (o el esquema es auditado) se envían como INSERT de varios registros
+		if isReturning(ts) {
+			sqlExec = bulkInsertValues(sqlExec, ts)
+		}
+		ts.SetQuery(sqlExec)
+		ts.SetData(data_insert)
+		return nil
+	} else {
+		return messageError(ts, "data.insert")
+	}
+}
+
+// bulkInsertValues convierte los grupos de COPY en sentencias INSERT de varios registros sin superar el limite de parámetros
+func bulkInsertValues(groups []adapters.DataExec, ts *domain.Transactions) []adapters.DataExec {
+	var sqlExec []adapters.DataExec
+	for _, group := range groups {
+		size := max(maxParameters/len(group.Columns), 1)
+		for chunk := range slices.Chunk(group.Rows, size) {
+			values, valuesExec := valuesRows(chunk, 0)
+			sqlExec = append(sqlExec, adapters.DataExec{
+				Querys:    fmt.Sprintf("INSERT INTO %s (%s) VALUES %s%s", group.Table, strings.Join(group.Columns, ", "), values, returning(ts)),
+				Values:    valuesExec,
+				Action:    group.Action,
+				Table:     group.Table,
+				Returning: true,
+				Audit:     auditExec(ts, ts.Schema().ParseInsert(), nil, false),
+			})
+		}
+	}
+	return sqlExec
+}
@@ -0,0 +1,67 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/deybin/pgorm/internal/adapters"
+	"github.com/deybin/pgorm/internal/core/builder"
+	"github.com/deybin/pgorm/migrator"
+)
+
+type bulkItem struct {
+	Id    string
+	Note  string
+	Stock int64
+}
+
+func (e bulkItem) Name() string      { return "bulk_items" }
+func (e bulkItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e bulkItem) Values() []any     { return migrator.EntityValues(e) }
+
+func TestBulkInsert_GroupsByColumns(t *testing.T) {
+	ts := transaction(schema[bulkItem]{}, adapters.INSERT,
+		bulkItem{Id: "1", Note: "a", Stock: 3},
+		bulkItem{Id: "2", Note: "b"},
+		bulkItem{Id: "3", Note: "c", Stock: 1},
+	)
+	if err := builder.BuilderBulkInsertGeneric(ts); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+
+	query := ts.Query()
+	if len(query) != 2 {
+		t.Fatalf("se esperaba 2 grupos de COPY, pero se obtuvo %d", len(query))
+	}
+	if query[0].Querys != "" || len(query[0].Rows) != 2 || len(query[0].Columns) != 3 || len(query[1].Columns) != 2 {
+		t.Errorf("grupos inesperados: %+v", query)
+	}
+	if len(ts.Data()) != 3 {
+		t.Errorf("se esperaba 3 registros, pero se obtuvo %d", len(ts.Data()))
+	}
+}
+
+func TestBulkInsert_ReturningUsesValues(t *testing.T) {
+	ts := transaction(schema[bulkItem]{}, adapters.INSERT, bulkItem{Id: "1", Note: "a"}, bulkItem{Id: "2", Note: "b"})
+	ts.SetReturning()
+	if err := builder.BuilderBulkInsertGeneric(ts); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+
+	query := ts.Query()
+	expected := "INSERT INTO bulk_items (id, note) VALUES ($1, $2), ($3, $4) RETURNING *"
+	if len(query) != 1 || query[0].Querys != expected || len(query[0].Rows) != 0 || !query[0].Returning {
+		t.Errorf("query inesperado: %+v", query)
+	}
+}
+
+func TestBulkInsert_RowWithoutColumns(t *testing.T) {
+	for _, returning := range []bool{false, true} {
+		ts := transaction(schema[bulkItem]{}, adapters.INSERT, bulkItem{Id: "1"}, bulkItem{})
+		if returning {
+			ts.SetReturning()
+		}
+		if err := builder.BuilderBulkInsertGeneric(ts); err == nil || err.Error() != "el registro 2 no tiene columnas para insertar" {
+			t.Errorf("returning %v: error inesperado: %v", returning, err)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/deybin/pgorm/internal/adapters"
+	"github.com/deybin/pgorm/internal/core/clause"
+	"github.com/deybin/pgorm/internal/core/domain"
+	"github.com/deybin/pgorm/migrator"
+)
+
+// bulkUpdateGroup agrupa los registros que actualizan las mismas columnas
+type bulkUpdateGroup struct {
+	keys   []string // llaves de CheckUpdateGeneric (incluye ADD_<columna>_<OPERACIÓN>)
+	values [][]any  // llave primaria, valores de keys y versión de cada registro
+	pks    [][]any  // valores de la llave primaria de cada registro
+}
+
+/*
+BuilderBulkUpdateGeneric genera un solo UPDATE ... FROM (VALUES ...) por cada grupo de registros que actualizan las mismas columnas.
+
+Cada EntityUpdate se valida con CheckUpdateGeneric y debe identificar el registro con condiciones `=` sobre todos los campos
+de la llave primaria. Los tipos de los valores se toman de la propia tabla con una primera fila `(NULL::tabla).columna`
+y los registros se dividen en varias sentencias para no superar el limite de parámetros de PostgreSQL.
+*/
+func BuilderBulkUpdateGeneric(ts *domain.Transactions) error {
+	if err := checkAudit(ts); err != nil {
+		return err
+	}
+	table := ts.Schema().Table().Name()
+	data := ts.Datos()
+	schemas := ts.Schema().ParseUpdate()
+	if len(data) == 0 {
+		return messageError(ts, "data.update")
+	}
+
+	var primaryKeys []string
+	for _, f := range schemas {
+		if f.PrimaryKey {
+			primaryKeys = append(primaryKeys, f.Name)
+		}
+	}
+	if len(primaryKeys) == 0 {
+		return fmt.Errorf("la tabla %s no tiene llave primaria para actualizar de forma masiva", table)
+	}
+	version := slices.IndexFunc(schemas, func(f migrator.Fields) bool { return f.Version })
+
+	var groups []*bulkUpdateGroup
+	indexGroups := make(map[string]*bulkUpdateGroup)
+	var data_update []map[string]any
+	for _, item := range data {
+		v := reflect.Indirect(reflect.ValueOf(item))
+		entity := v.FieldByName("Entity").Interface()
+		var set []string
+		if field := v.FieldByName("Set"); field.IsValid() {
+			set, _ = field.Interface().([]string)
+		}
+		preArray, err := migrator.CheckUpdateGenericContext(ts.Context(), schemas, entity, set...)
+		if err != nil {
+			return err
+		}
+		if len(preArray) == 0 {
+			continue
+		}
+
+		conditions, _ := v.FieldByName("Conditions").Interface().([]migrator.Where)
+		pk, err := bulkPrimaryKey(ts.Context(), schemas, primaryKeys, conditions)
+		if err != nil {
+			return err
+		}
+
+		keys := slices.Sorted(maps.Keys(preArray))
+		row := slices.Clone(pk)
+		for _, k := range keys {
+			column, _ := arithmeticColumn(k)
+			if slices.Contains(primaryKeys, column) {
+				return fmt.Errorf("no se puede actualizar la llave primaria %s de forma masiva", column)
+			}
+			row = append(row, preArray[k])
+		}
+		if version >= 0 {
+			row = append(row, schemas[version].FieldValue(reflect.Indirect(reflect.ValueOf(entity))).Interface())
+		}
+
+		groupKey := strings.Join(keys, ",")
+		group, ok := indexGroups[groupKey]
+		if !ok {
+			group = &bulkUpdateGroup{keys: keys}
+			indexGroups[groupKey] = group
+			groups = append(groups, group)
+		}
+		group.values = append(group.values, row)
+		group.pks = append(group.pks, pk)
+		data_update = append(data_update, preArray)
+	}
+	if len(data_update) == 0 {
+		return messageError(ts, "data.update.filtered")
+	}
+
+	var sqlExec []adapters.DataExec
+	for _, group := range groups {
+		sqlExec = append(sqlExec, bulkUpdateStatements(ts, schemas, primaryKeys, version, group)...)
+	}
+	ts.SetQuery(sqlExec)
+	ts.SetData(data_update)
+	return nil
+}
+
+// bulkPrimaryKey obtiene los valores de la llave primaria de las condiciones `=` de un EntityUpdate
+func bulkPrimaryKey(ctx context.Context, schemas []migrator.Fields, primaryKeys []string, conditions []migrator.Where) ([]any, error) {
+	conditions, err := migrator.CheckWhereGenericContext(ctx, schemas, conditions...)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]any)
+	for _, c := range conditions {
+		if c.Condition != string(clause.I) || c.Clause == string(clause.OR) || !slices.Contains(primaryKeys, c.Field) {
+			return nil, fmt.Errorf("la actualización masiva solo acepta condiciones = sobre la llave primaria (%s)", strings.Join(primaryKeys, ", "))
+		}
+		values[c.Field] = c.Value
+	}
+	pk := make([]any, 0, len(primaryKeys))
+	for _, k := range primaryKeys {
+		v, ok := values[k]
+		if !ok {
+			return nil, fmt.Errorf("falta el valor de la llave primaria %s para la actualización masiva", k)
+		}
+		pk = append(pk, v)
+	}
+	return pk, nil
+}
+
+// bulkUpdateStatements genera las sentencias de un grupo, cada una con tantos registros como permita el limite de parámetros
+func bulkUpdateStatements(ts *domain.Transactions, schemas []migrator.Fields, primaryKeys []string, version int, group *bulkUpdateGroup) []adapters.DataExec {
+	table := ts.Schema().Table().Name()
+	expect, maxRows := ts.Expect()
+
+	columns := slices.Clone(primaryKeys)
+	var setters, guards []string
+	for _, k := range group.keys {
+		column, operator := arithmeticColumn(k)
+		columns = append(columns, column)
+		if operator == "" {
+			setters = append(setters, fmt.Sprintf("%s = v.%s", column, column))
+			continue
+		}
+		expr := fmt.Sprintf("%s.%s %s v.%s", table, column, operator, column)
+		setters = append(setters, fmt.Sprintf("%s = %s", column, expr))
+		if idx := slices.IndexFunc(schemas, func(f migrator.Fields) bool { return f.Name == column }); idx >= 0 {
+			guards = append(guards, arithmeticGuards(expr, schemas[idx].ValidateType)...)
+		}
+	}
+	for _, k := range primaryKeys {
+		guards = append([]string{fmt.Sprintf("%s.%s = v.%s", table, k, k)}, guards...)
+	}
+	if version >= 0 {
+		column := schemas[version].Name
+		columns = append(columns, column)
+		setters = append(setters, fmt.Sprintf("%s = %s.%s + 1", column, table, column))
+		guards = append(guards, fmt.Sprintf("%s.%s = v.%s", table, column, column))
+	}
+
+	// parámetros fuera de VALUES: la fecha de los campos autoUpdateTime
+	var extra []any
+	for _, f := range schemas {
+		if f.AutoUpdateTime {
+			extra = append(extra, f.TimeValue())
+			setters = append(setters, fmt.Sprintf("%s = $%d", f.Name, len(extra)))
+		}
+	}
+
+	// la primera fila de VALUES define el tipo de cada columna, como su llave es NULL nunca coincide con un registro
+	typed := make([]string, len(columns))
+	for i, c := range columns {
+		typed[i] = fmt.Sprintf("(NULL::%s).%s", table, c)
+	}
+
+	size := (maxParameters - len(extra)) / len(columns)
+	var sqlExec []adapters.DataExec
+	for start := 0; start < len(group.values); start += size {
+		end := min(start+size, len(group.values))
+		values, valuesExec := valuesRows(group.values[start:end], len(extra))
+		sqlPreparate := fmt.Sprintf("UPDATE %s SET %s FROM (VALUES (%s), %s) AS v(%s) WHERE %s%s",
+			table, strings.Join(setters, ", "), strings.Join(typed, ", "), values, strings.Join(columns, ", "),
+			strings.Join(guards, " AND "), qualifiedReturning(ts, table))
+
+		var keys []string
+		for _, pk := range group.pks[start:end] {
+			keys = append(keys, rowKey(primaryKeys, pk))
+		}
+		sqlExec = append(sqlExec, adapters.DataExec{
+			Querys:    sqlPreparate,
+			Values:    append(slices.Clone(extra), valuesExec...),
+			Action:    ts.Action(),
+			Table:     table,
+			Returning: isReturning(ts),
+			Version:   version >= 0,
+			Count:     int64(end - start),
+			Key:       strings.Join(keys, "; "),
+			Expect:    expect,
+			MaxRows:   maxRows,
+			Audit:     auditKeysExec(ts, primaryKeys, group.pks[start:end]),
+		})
+	}
+	return sqlExec
+}
+
+// rowKey describe un registro con los valores de su llave primaria (por ejemplo `id=10`)
+func rowKey(primaryKeys []string, values []any) string {
+	keys := make([]string, len(primaryKeys))
+	for i, k := range primaryKeys {
+		keys[i] = fmt.Sprintf("%s=%v", k, values[i])
+	}
+	return strings.Join(keys, ", ")
+}
+
+// qualifiedReturning antepone el nombre de la tabla a las columnas de RETURNING, en un UPDATE ... FROM las columnas sin tabla son ambiguas
+func qualifiedReturning(ts *domain.Transactions, table string) string {
+	r := returning(ts)
+	if r == "" {
+		return ""
+	}
+	columns := strings.Split(strings.TrimPrefix(r, " RETURNING "), ", ")
+	for i, c := range columns {
+		if !strings.ContainsAny(c, ".( ") {
+			columns[i] = table + "." + c
+		}
+	}
+	return " RETURNING " + strings.Join(columns, ", ")
+}
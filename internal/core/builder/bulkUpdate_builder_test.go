@@ -0,0 +1,55 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/deybin/pgorm/internal/adapters"
+	"github.com/deybin/pgorm/internal/core/builder"
+	"github.com/deybin/pgorm/migrator"
+)
+
+type bulkStock struct {
+	Id      string  `validate:"primaryKey"`
+	Nombre  string  `validate:"update"`
+	Price   float64 `validate:"update"`
+	Stock   int64   `validate:"update;sum" validateType:"max=1000"`
+	Version int64   `validate:"version"`
+}
+
+func (e bulkStock) Name() string      { return "bulk_stock" }
+func (e bulkStock) Columns() []string { return migrator.EntityColumns(e) }
+func (e bulkStock) Values() []any     { return migrator.EntityValues(e) }
+
+func TestBulkUpdate_GroupsBySet(t *testing.T) {
+	ts := transaction(schema[bulkStock]{}, adapters.UPDATE,
+		migrator.EntityUpdate{Entity: bulkStock{Nombre: "Teclado", Stock: 2, Version: 1}, Conditions: whereId("1")},
+		migrator.EntityUpdate{Entity: bulkStock{Nombre: "Mouse", Stock: 5, Version: 4}, Conditions: whereId("2")},
+		migrator.EntityUpdate{Entity: bulkStock{Price: 9.5, Version: 2}, Conditions: whereId("3")},
+	)
+	if err := builder.BuilderBulkUpdateGeneric(ts); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	query := ts.Query()
+	if len(query) != 2 {
+		t.Fatalf("se esperaba 2 sentencias, pero se obtuvo %d", len(query))
+	}
+	expected := "UPDATE bulk_stock SET stock = bulk_stock.stock + v.stock, nombre = v.nombre, version = bulk_stock.version + 1 " +
+		"FROM (VALUES ((NULL::bulk_stock).id, (NULL::bulk_stock).stock, (NULL::bulk_stock).nombre, (NULL::bulk_stock).version), ($1, $2, $3, $4), ($5, $6, $7, $8)) AS v(id, stock, nombre, version) " +
+		"WHERE bulk_stock.id = v.id AND bulk_stock.stock + v.stock <= 1000 AND bulk_stock.stock + v.stock >= 0 AND bulk_stock.version = v.version"
+	if query[0].Querys != expected {
+		t.Errorf("query inesperado: %q", query[0].Querys)
+	}
+	if len(query[0].Values) != 8 || query[0].Values[4] != "2" || query[0].Count != 2 || query[0].Key != "id=1; id=2" {
+		t.Errorf("valores inesperados: %+v", query[0])
+	}
+}
+
+func TestBulkUpdate_OnlyPrimaryKey(t *testing.T) {
+	ts := transaction(schema[bulkStock]{}, adapters.UPDATE, migrator.EntityUpdate{
+		Entity:     bulkStock{Price: 1},
+		Conditions: []migrator.Where{{Clause: "WHERE", Condition: ">", Field: "id", Value: "1"}},
+	})
+	if err := builder.BuilderBulkUpdateGeneric(ts); err == nil {
+		t.Errorf("se esperaba un error por condición distinta de la llave primaria")
+	}
+}
@@ -1,16 +1,41 @@
 package builder
 
 import (
-	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/deybin/pgorm/internal/adapters"
 	"github.com/deybin/pgorm/internal/core/domain"
 	"github.com/deybin/pgorm/migrator"
 )
 
+// deleteMode indica como se eliminan los registros que cumplen las condiciones
+type deleteMode uint8
+
+const (
+	softOrHard deleteMode = iota // eliminación lógica si la tabla la usa, si no eliminación física
+	hard                         // eliminación física aunque la tabla use eliminación lógica
+	restore                      // revierte la eliminación lógica
+)
+
+// BuilderDeleteGeneric genera la sentencia de eliminación, si el esquema tiene un campo `softDelete` se genera un UPDATE que lo establece
 func BuilderDeleteGeneric(ts *domain.Transactions, data []migrator.Where) error {
+	return builderDelete(ts, data, softOrHard)
+}
+
+// BuilderHardDeleteGeneric genera un DELETE FROM aunque el esquema use eliminación lógica
+func BuilderHardDeleteGeneric(ts *domain.Transactions, data []migrator.Where) error {
+	return builderDelete(ts, data, hard)
+}
+
+// BuilderRestoreGeneric genera el UPDATE que limpia el campo `softDelete` de los registros eliminados lógicamente
+func BuilderRestoreGeneric(ts *domain.Transactions, data []migrator.Where) error {
+	return builderDelete(ts, data, restore)
+}
+
+func builderDelete(ts *domain.Transactions, data []migrator.Where, mode deleteMode) error {
+	if err := checkAudit(ts); err != nil {
+		return err
+	}
 	table := ts.Schema().Table().Name()
 	schemas := ts.Schema().ParseUpdate()
 	length := len(data)
@@ -19,33 +44,47 @@ func BuilderDeleteGeneric(ts *domain.Transactions, data []migrator.Where) error
 		var sqlExec = make([]adapters.DataExec, 0)
 		// var data_delete []map[string]any
 
-		preArray, err := migrator.CheckWhereGeneric(schemas, data...)
+		preArray, err := migrator.CheckWhereGenericContext(ts.Context(), schemas, data...)
 		if err != nil {
 			return err
 		}
 
-		sqlWherePreparateDelete := ""
-		var i uint64
+		var i int
+		conditions, valuesExec := whereConditions(preArray, &i)
 
-		var valuesExec []interface{}
-
-		char := "$"
-		var wheres []string
-
-		for _, v := range preArray {
+		var sqlPreparate string
+		softDelete, ok := migrator.SoftDeleteField(schemas)
+		switch {
+		case mode == restore && !ok:
+			return fmt.Errorf("la tabla %s no usa eliminación lógica (softDelete)", table)
+		case mode == restore:
+			sqlWherePreparate := whereClause(conditions, []string{softDelete.Name + " IS NOT NULL"})
+			sqlPreparate = fmt.Sprintf("UPDATE %s SET %s= NULL %s%s", table, softDelete.Name, sqlWherePreparate, returning(ts))
+		case mode == softOrHard && ok:
 			i++
-			wheres = append(wheres, fmt.Sprintf("%s %s %s %s%d", v.Clause, v.Field, v.Condition, char, i))
-			valuesExec = append(valuesExec, v.Value)
+			valuesExec = append(valuesExec, softDelete.TimeValue())
+			sqlWherePreparate := whereClause(conditions, []string{softDelete.Name + " IS NULL"})
+			sqlPreparate = fmt.Sprintf("UPDATE %s SET %s= $%d %s%s", table, softDelete.Name, i, sqlWherePreparate, returning(ts))
+		default:
+			sqlWherePreparateDelete := whereClause(conditions, nil)
+			sqlPreparate = fmt.Sprintf("DELETE FROM %s %s%s", table, sqlWherePreparateDelete, returning(ts))
 		}
 
-		sqlWherePreparateDelete = strings.Join(wheres, " ")
-
-		sqlPreparate := fmt.Sprintf("DELETE FROM %s %s", table, sqlWherePreparateDelete)
-		sqlExec = append(sqlExec, adapters.DataExec{Querys: sqlPreparate, Values: valuesExec, Action: ts.Action()})
+		expect, maxRows := ts.Expect()
+		sqlExec = append(sqlExec, adapters.DataExec{
+			Querys:    sqlPreparate,
+			Values:    valuesExec,
+			Action:    ts.Action(),
+			Table:     table,
+			Returning: isReturning(ts),
+			Audit:     auditExec(ts, schemas, preArray, mode == restore || (mode == softOrHard && ok)),
+			Expect:    expect,
+			MaxRows:   maxRows,
+		})
 
 		ts.SetQuery(sqlExec)
 		return nil
 	} else {
-		return errors.New("no existen datos para eliminar")
+		return messageError(ts, "data.delete")
 	}
 }
@@ -0,0 +1,69 @@
+package builder_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deybin/pgorm/internal/adapters"
+	"github.com/deybin/pgorm/internal/core/builder"
+	"github.com/deybin/pgorm/internal/core/domain"
+	"github.com/deybin/pgorm/migrator"
+)
+
+type softItem struct {
+	Id         string     `validate:"primaryKey"`
+	Deleted_at *time.Time `validate:"softDelete"`
+}
+
+func (e softItem) Name() string      { return "soft_items" }
+func (e softItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e softItem) Values() []any     { return migrator.EntityValues(e) }
+
+type hardItem struct {
+	Id string `validate:"primaryKey"`
+}
+
+func (e hardItem) Name() string      { return "hard_items" }
+func (e hardItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e hardItem) Values() []any     { return migrator.EntityValues(e) }
+
+func TestDelete_SoftDelete(t *testing.T) {
+	tests := []struct {
+		build    func(*domain.Transactions, []migrator.Where) error
+		expected string
+	}{
+		{builder.BuilderDeleteGeneric, "UPDATE soft_items SET deleted_at= $2 WHERE (id = $1) AND deleted_at IS NULL"},
+		{builder.BuilderRestoreGeneric, "UPDATE soft_items SET deleted_at= NULL WHERE (id = $1) AND deleted_at IS NOT NULL"},
+		{builder.BuilderHardDeleteGeneric, "DELETE FROM soft_items WHERE id = $1"},
+	}
+	for _, tt := range tests {
+		ts := transaction(schema[softItem]{}, adapters.DELETE)
+		if err := tt.build(ts, whereId("1")); err != nil {
+			t.Errorf("no se esperaba este error: %v", err)
+			continue
+		}
+		if query := ts.Query()[0].Querys; query != tt.expected {
+			t.Errorf("query inesperado: %q", query)
+		}
+	}
+
+	ts := transaction(schema[hardItem]{}, adapters.DELETE)
+	if err := builder.BuilderDeleteGeneric(ts, whereId("1")); err != nil || ts.Query()[0].Querys != "DELETE FROM hard_items WHERE id = $1" {
+		t.Errorf("sin softDelete se esperaba DELETE FROM: %v %+v", err, ts.Query())
+	}
+	if err := builder.BuilderRestoreGeneric(transaction(schema[hardItem]{}, adapters.UPDATE), whereId("1")); err == nil {
+		t.Errorf("se esperaba un error al restaurar una tabla sin softDelete")
+	}
+}
+
+func TestDelete_ExpectRows(t *testing.T) {
+	ts := transaction(schema[hardItem]{}, adapters.DELETE)
+	ts.SetExpect(1)
+	if err := builder.BuilderDeleteGeneric(ts, whereId("1")); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	query := ts.Query()[0]
+	if !query.Expect || query.MaxRows != 1 || query.Table != "hard_items" {
+		t.Errorf("sentencia inesperada: %+v", query)
+	}
+}
@@ -0,0 +1,11 @@
+package builder
+
+import (
+	"github.com/deybin/pgorm/internal/core/domain"
+	"github.com/deybin/pgorm/logger"
+)
+
+// messageError retorna el mensaje del catalogo con el código indicado en el idioma del contexto de la transacción
+func messageError(ts *domain.Transactions, code string, args ...any) error {
+	return logger.Errorf(logger.LanguageContext(ts.Context()), code, args...)
+}
@@ -1,8 +1,9 @@
 package builder
 
 import (
-	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/deybin/pgorm/internal/adapters"
@@ -11,6 +12,9 @@ import (
 )
 
 func BuilderInsertGeneric(ts *domain.Transactions) error {
+	if err := checkAudit(ts); err != nil {
+		return err
+	}
 	table := ts.Schema().Table().Name()
 	data := ts.Datos()
 	schema := ts.Schema().ParseInsert()
@@ -20,7 +24,7 @@ func BuilderInsertGeneric(ts *domain.Transactions) error {
 		var data_insert []map[string]any
 
 		for _, item := range data {
-			preArray, err := migrator.CheckInsertGeneric(schema, item)
+			preArray, err := migrator.CheckInsertGenericContext(ts.Context(), schema, item)
 			if err == nil {
 				data_insert = append(data_insert, preArray)
 				var column []string
@@ -28,18 +32,22 @@ func BuilderInsertGeneric(ts *domain.Transactions) error {
 				var i int
 				var valuesExec []any
 				char := "$"
-				for k, v := range preArray {
+				// columnas en orden fijo para que el texto sql sea el mismo entre registros (cache de sentencias de pgx)
+				for _, k := range slices.Sorted(maps.Keys(preArray)) {
 					i++
 					column = append(column, k)
 					values = append(values, fmt.Sprintf("%s%d", char, i))
-					valuesExec = append(valuesExec, v)
+					valuesExec = append(valuesExec, preArray[k])
 				}
 
-				sqlPreparate := fmt.Sprintf("INSERT INTO %s (%s) VALUES(%s)", table, strings.Join(column, ", "), strings.Join(values, ", "))
+				sqlPreparate := fmt.Sprintf("INSERT INTO %s (%s) VALUES(%s)%s", table, strings.Join(column, ", "), strings.Join(values, ", "), returning(ts))
 				sqlExec = append(sqlExec, adapters.DataExec{
-					Querys: sqlPreparate,
-					Values: valuesExec,
-					Action: ts.Action(),
+					Querys:    sqlPreparate,
+					Values:    valuesExec,
+					Action:    ts.Action(),
+					Table:     table,
+					Returning: isReturning(ts),
+					Audit:     auditExec(ts, schema, nil, false),
 				})
 			} else {
 				return err
@@ -49,6 +57,6 @@ func BuilderInsertGeneric(ts *domain.Transactions) error {
 		ts.SetData(data_insert)
 		return nil
 	} else {
-		return errors.New("no existen datos para insertar")
+		return messageError(ts, "data.insert")
 	}
 }
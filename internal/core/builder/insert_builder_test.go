@@ -0,0 +1,41 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/deybin/pgorm/internal/adapters"
+	"github.com/deybin/pgorm/internal/core/builder"
+	"github.com/deybin/pgorm/migrator"
+)
+
+type insertItem struct {
+	Id     string `validate:"primaryKey;required"`
+	Nombre string `validate:"required" validateType:"case=lowercase"`
+	Price  float64
+	Stock  int64
+}
+
+func (e insertItem) Name() string      { return "insert_items" }
+func (e insertItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e insertItem) Values() []any     { return migrator.EntityValues(e) }
+
+func TestInsert_Deterministic(t *testing.T) {
+	ts := transaction(schema[insertItem]{}, adapters.INSERT,
+		insertItem{Id: "1", Nombre: "Teclado", Price: 20.5, Stock: 3},
+		insertItem{Id: "2", Nombre: "Mouse", Price: 10, Stock: 8},
+	)
+	if err := builder.BuilderInsertGeneric(ts); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+
+	query := ts.Query()
+	expected := "INSERT INTO insert_items (id, nombre, price, stock) VALUES($1, $2, $3, $4)"
+	for _, item := range query {
+		if item.Querys != expected || item.Returning {
+			t.Errorf("query inesperado: %q", item.Querys)
+		}
+	}
+	if query[0].Values[1] != "teclado" || query[1].Values[0] != "2" {
+		t.Errorf("valores inesperados: %v, %v", query[0].Values, query[1].Values)
+	}
+}
@@ -1,9 +1,11 @@
 package builder
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/deybin/pgorm/internal/core/domain"
+	"github.com/deybin/pgorm/migrator"
 )
 
 func BuildQuery(q *domain.Sintaxis) string {
@@ -13,7 +15,7 @@ func BuildQuery(q *domain.Sintaxis) string {
 		querySql.WriteString(q.Select_field.Build())
 		querySql.WriteString(q.From_field.Build())
 		querySql.WriteString(q.Join_field.Build())
-		querySql.WriteString(q.Where_field.Build())
+		querySql.WriteString(deletedScope(q, q.Where_field.Build()))
 		// fmt.Println(q.Where_field)
 		q.Args_field = q.Where_field.FindArguments()
 		q.ArgsLen_field = q.Where_field.FindArgumentsLen()
@@ -26,3 +28,46 @@ func BuildQuery(q *domain.Sintaxis) string {
 
 	return querySql.String()
 }
+
+// deletedScope agrega a la cláusula WHERE el filtro de registros eliminados lógicamente del modelo, las condiciones existentes se agrupan entre paréntesis
+func deletedScope(q *domain.Sintaxis, where string) string {
+	if q.Model_field == nil || q.Deleted_field == domain.IncludeDeleted {
+		return where
+	}
+	field, ok := migrator.SoftDeleteField(q.Model_field.ParseDelete())
+	if !ok {
+		return where
+	}
+
+	table := scopeTable(q.From_field.Table, q.Model_field.Table().Name())
+	scope := fmt.Sprintf("%s.%s IS NULL", table, field.Name)
+	if q.Deleted_field == domain.OnlyDeleted {
+		scope = fmt.Sprintf("%s.%s IS NOT NULL", table, field.Name)
+	}
+	if conditions, ok := strings.CutPrefix(where, "WHERE "); ok {
+		return fmt.Sprintf("WHERE (%s) AND %s ", strings.TrimSpace(conditions), scope)
+	}
+	return fmt.Sprintf("WHERE %s ", scope)
+}
+
+// scopeTable devuelve el nombre con el que se referencia la tabla del modelo en la consulta, el alias si FROM lo indica (`products p` o `products AS p`)
+func scopeTable(from string, table string) string {
+	words := strings.Fields(from)
+	if len(words) == 0 {
+		return table
+	}
+	name := words[0]
+	if _, after, ok := strings.Cut(name, "."); ok {
+		name = after
+	}
+	if name != table {
+		return table
+	}
+	switch {
+	case len(words) == 2:
+		return words[1]
+	case len(words) == 3 && strings.EqualFold(words[1], "AS"):
+		return words[2]
+	}
+	return table
+}
@@ -0,0 +1,42 @@
+package builder_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deybin/pgorm/internal/core/builder"
+	"github.com/deybin/pgorm/internal/core/clause"
+	"github.com/deybin/pgorm/internal/core/domain"
+	"github.com/deybin/pgorm/migrator"
+)
+
+type scopedItem struct {
+	Id         string     `validate:"primaryKey"`
+	Code       string     `validate:"where"`
+	Deleted_at *time.Time `validate:"softDelete"`
+}
+
+func (e scopedItem) Name() string      { return "scoped_items" }
+func (e scopedItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e scopedItem) Values() []any     { return migrator.EntityValues(e) }
+
+func TestQuery_SoftDeleteScope(t *testing.T) {
+	model := func() *domain.Sintaxis { return domain.NewSintaxis().Model(schema[scopedItem]{}) }
+	tests := []struct {
+		query    *domain.Sintaxis
+		expected string
+	}{
+		{model().Select().Where("code", clause.I, "T-1"), "SELECT * FROM scoped_items WHERE (code = $1) AND scoped_items.deleted_at IS NULL "},
+		{model().Select().OnlyDeleted(), "SELECT * FROM scoped_items WHERE scoped_items.deleted_at IS NOT NULL "},
+		{model().Select().WithDeleted(), "SELECT * FROM scoped_items "},
+		{model().From("scoped_items s").Select().Where("s.code", clause.I, "T-1"), "SELECT * FROM scoped_items s WHERE (s.code = $1) AND s.deleted_at IS NULL "},
+		{model().From("public.scoped_items AS s").Select(), "SELECT * FROM public.scoped_items AS s WHERE s.deleted_at IS NULL "},
+		// sin Model no se conoce el esquema y no se filtra
+		{domain.NewSintaxis().From("scoped_items").Select(), "SELECT * FROM scoped_items "},
+	}
+	for _, tt := range tests {
+		if sql := builder.BuildQuery(tt.query); sql != tt.expected {
+			t.Errorf("consulta inesperada: %q", sql)
+		}
+	}
+}
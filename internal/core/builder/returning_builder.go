@@ -0,0 +1,51 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/deybin/pgorm/internal/adapters"
+	"github.com/deybin/pgorm/internal/core/domain"
+)
+
+// maxParameters es el limite de parámetros ($n) que acepta PostgreSQL en una sentencia
+const maxParameters = adapters.MaxParameters
+
+// returning devuelve la cláusula RETURNING solicitada para la transacción o vació si no se solicito, los esquemas auditados siempre retornan todas las columnas
+func returning(ts *domain.Transactions) string {
+	if audited(ts) {
+		return " RETURNING *"
+	}
+	if r := ts.Returning(); r != "" {
+		return " RETURNING " + r
+	}
+	return ""
+}
+
+// isReturning indica si las sentencias de la transacción tienen cláusula RETURNING
+func isReturning(ts *domain.Transactions) bool {
+	return ts.Returning() != "" || audited(ts)
+}
+
+/*
+valuesRows genera la lista de VALUES de un INSERT de varios registros comenzando en el placeholder start+1.
+
+	Return
+		- (string) lista de tuplas `($1, $2), ($3, $4)`
+		- ([]any) valores en el orden de los placeholders
+*/
+func valuesRows(rows [][]any, start int) (string, []any) {
+	tuples := make([]string, 0, len(rows))
+	var valuesExec []any
+	i := start
+	for _, row := range rows {
+		placeholders := make([]string, len(row))
+		for j, v := range row {
+			i++
+			placeholders[j] = fmt.Sprintf("$%d", i)
+			valuesExec = append(valuesExec, v)
+		}
+		tuples = append(tuples, "("+strings.Join(placeholders, ", ")+")")
+	}
+	return strings.Join(tuples, ", "), valuesExec
+}
@@ -0,0 +1,56 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/deybin/pgorm/internal/adapters"
+	"github.com/deybin/pgorm/internal/core/builder"
+	"github.com/deybin/pgorm/migrator"
+)
+
+type returningItem struct {
+	Id    string `validate:"primaryKey;required"`
+	Stock int64  `validate:"update"`
+}
+
+func (e returningItem) Name() string      { return "returning_items" }
+func (e returningItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e returningItem) Values() []any     { return migrator.EntityValues(e) }
+
+func TestReturning_Insert(t *testing.T) {
+	ts := transaction(schema[returningItem]{}, adapters.INSERT, returningItem{Id: "1"})
+	ts.SetReturning("id", "stock")
+	if err := builder.BuilderInsertGeneric(ts); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	query := ts.Query()[0]
+	if query.Querys != `INSERT INTO returning_items (id) VALUES($1) RETURNING "id", "stock"` || !query.Returning {
+		t.Errorf("query inesperado: %q", query.Querys)
+	}
+}
+
+func TestReturning_Update(t *testing.T) {
+	ts := transaction(schema[returningItem]{}, adapters.UPDATE, migrator.EntityUpdate{
+		Entity:     returningItem{Stock: 4},
+		Conditions: []migrator.Where{{Clause: "WHERE", Condition: "=", Field: "id", Value: "1"}},
+	})
+	ts.SetReturning()
+	if err := builder.BuilderUpdateGeneric(ts); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	query := ts.Query()[0]
+	if query.Querys != "UPDATE returning_items SET stock= $1 WHERE id = $2 RETURNING *" || !query.Returning {
+		t.Errorf("query inesperado: %q", query.Querys)
+	}
+}
+
+func TestReturning_QuotesColumns(t *testing.T) {
+	ts := transaction(schema[returningItem]{}, adapters.INSERT, returningItem{Id: "1"})
+	ts.SetReturning("id; DROP TABLE returning_items")
+	if err := builder.BuilderInsertGeneric(ts); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	if query := ts.Query()[0].Querys; query != `INSERT INTO returning_items (id) VALUES($1) RETURNING "id; DROP TABLE returning_items"` {
+		t.Errorf("las columnas deben escribirse entre comillas: %q", query)
+	}
+}
@@ -1,9 +1,10 @@
 package builder
 
 import (
-	"errors"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/deybin/pgorm/internal/adapters"
@@ -11,21 +12,40 @@ import (
 	"github.com/deybin/pgorm/migrator"
 )
 
+// arithmeticKeys relaciona el sufijo de las llaves generadas por CheckUpdateGeneric con su operador sql
+var arithmeticKeys = []struct {
+	suffix   string
+	operator string
+}{
+	{"_SUMA", "+"},
+	{"_SUBTRACTION", "-"},
+	{"_MULTIPLY", "*"},
+	{"_DIVIDE", "/"},
+}
+
 func BuilderUpdateGeneric(ts *domain.Transactions) error {
+	if err := checkAudit(ts); err != nil {
+		return err
+	}
 	table := ts.Schema().Table().Name()
 	data := ts.Datos()
 	schemas := ts.Schema().ParseUpdate()
 	length := len(data)
+	expect, maxRows := ts.Expect()
 
 	if length > 0 {
 		var sqlExec = make([]adapters.DataExec, 0)
 		var data_update []map[string]any
 		for _, item := range data {
-			v := reflect.ValueOf(item)
+			v := reflect.Indirect(reflect.ValueOf(item))
 			valData := v.FieldByName("Entity")
 			valueData := valData.Interface()
 
-			preArray, err := migrator.CheckUpdateGeneric(schemas, valueData)
+			var set []string
+			if field := v.FieldByName("Set"); field.IsValid() {
+				set, _ = field.Interface().([]string)
+			}
+			preArray, err := migrator.CheckUpdateGenericContext(ts.Context(), schemas, valueData, set...)
 			if err != nil {
 				return err
 			}
@@ -39,7 +59,7 @@ func BuilderUpdateGeneric(ts *domain.Transactions) error {
 			var preArray_where []migrator.Where
 			lengthWhere := len(value)
 			if lengthWhere > 0 {
-				preArray, err := migrator.CheckWhereGeneric(schemas, value...)
+				preArray, err := migrator.CheckWhereGenericContext(ts.Context(), schemas, value...)
 				if err != nil {
 					return err
 				}
@@ -48,41 +68,74 @@ func BuilderUpdateGeneric(ts *domain.Transactions) error {
 
 			data_update = append(data_update, preArray)
 			var setters []string
+			var guards []string
 
-			sqlWherePreparateUpdate := ""
-			var i uint64
+			var i int
 			var valuesExec []interface{}
 			char := "$"
-			for k, v := range preArray {
+			for _, k := range slices.Sorted(maps.Keys(preArray)) {
 				i++
-				setters = append(setters, fmt.Sprintf("%s= %s%d", k, char, i))
-				valuesExec = append(valuesExec, v)
-			}
+				column, operator := arithmeticColumn(k)
+				if operator == "" {
+					setters = append(setters, fmt.Sprintf("%s= %s%d", k, char, i))
+					valuesExec = append(valuesExec, preArray[k])
+					continue
+				}
 
-			if lengthWhere > 0 {
+				expr := fmt.Sprintf("%s %s %s%d", column, operator, char, i)
+				setters = append(setters, fmt.Sprintf("%s= %s", column, expr))
+				valuesExec = append(valuesExec, preArray[k])
+
+				idx := slices.IndexFunc(schemas, func(f migrator.Fields) bool { return f.Name == column })
+				if idx >= 0 {
+					guards = append(guards, arithmeticGuards(expr, schemas[idx].ValidateType)...)
+				}
+			}
 
-				var wheres []string
-				for _, v := range preArray_where {
+			for _, f := range schemas {
+				if f.AutoUpdateTime {
 					i++
-					wheres = append(wheres, fmt.Sprintf("%s %s %s %s%d", v.Clause, v.Field, v.Condition, char, i))
-					valuesExec = append(valuesExec, v.Value)
+					setters = append(setters, fmt.Sprintf("%s= %s%d", f.Name, char, i))
+					valuesExec = append(valuesExec, f.TimeValue())
 				}
+			}
 
-				sqlWherePreparateUpdate = strings.Join(wheres, " ")
-				// fmt.Println(sqlWherePreparateUpdate)
+			var conditions string
+			if lengthWhere > 0 {
+				var valuesWhere []any
+				conditions, valuesWhere = whereConditions(preArray_where, &i)
+				valuesExec = append(valuesExec, valuesWhere...)
+			}
 
+			version := slices.IndexFunc(schemas, func(f migrator.Fields) bool { return f.Version })
+			if version >= 0 {
+				column := schemas[version].Name
+				current := schemas[version].FieldValue(reflect.Indirect(reflect.ValueOf(valueData))).Interface()
+				i++
+				setters = append(setters, fmt.Sprintf("%s= %s + 1", column, column))
+				guards = append([]string{fmt.Sprintf("%s = %s%d", column, char, i)}, guards...)
+				valuesExec = append(valuesExec, current)
 			}
-			sqlPreparate := fmt.Sprintf("UPDATE %s SET %s %s", table, strings.Join(setters, ", "), sqlWherePreparateUpdate)
+			sqlWherePreparateUpdate := whereClause(conditions, guards)
+
+			sqlPreparate := fmt.Sprintf("UPDATE %s SET %s %s%s", table, strings.Join(setters, ", "), sqlWherePreparateUpdate, returning(ts))
 			sqlExec = append(sqlExec, adapters.DataExec{
-				Querys: sqlPreparate,
-				Values: valuesExec,
-				Action: ts.Action(),
+				Querys:    sqlPreparate,
+				Values:    valuesExec,
+				Action:    ts.Action(),
+				Table:     table,
+				Returning: isReturning(ts),
+				Audit:     auditExec(ts, schemas, preArray_where, true),
+				Version:   version >= 0,
+				Key:       conditionsKey(schemas, preArray_where),
+				Expect:    expect,
+				MaxRows:   maxRows,
 			})
 
 		}
 
 		if len(data_update) <= 0 {
-			return errors.New("al realizar validaciones se filtro datos y se quedo sin información para actualizar")
+			return messageError(ts, "data.update.filtered")
 		}
 		ts.SetQuery(sqlExec)
 		ts.SetData(data_update)
@@ -90,6 +143,77 @@ func BuilderUpdateGeneric(ts *domain.Transactions) error {
 
 		return nil
 	} else {
-		return errors.New("no existen datos para actualizar")
+		return messageError(ts, "data.update")
+	}
+}
+
+// arithmeticColumn obtiene la columna y el operador de una llave `ADD_<columna>_<OPERACIÓN>`, operador vació si es una asignación simple
+func arithmeticColumn(key string) (string, string) {
+	name, ok := strings.CutPrefix(key, "ADD_")
+	if !ok {
+		return key, ""
+	}
+	for _, v := range arithmeticKeys {
+		if column, ok := strings.CutSuffix(name, v.suffix); ok {
+			return column, v.operator
+		}
+	}
+	return key, ""
+}
+
+/*
+arithmeticGuards genera las condiciones que impiden que el resultado de la operación salga de los limites del campo.
+
+Si el resultado no cumple los limites la fila no se actualiza.
+*/
+func arithmeticGuards(expr string, validateType any) []string {
+	var guards []string
+	switch t := validateType.(type) {
+	case migrator.TypeInt64:
+		if t.Max != 0 {
+			guards = append(guards, fmt.Sprintf("%s <= %d", expr, t.Max))
+		}
+		if t.Min != 0 {
+			guards = append(guards, fmt.Sprintf("%s >= %d", expr, t.Min))
+		}
+		if !t.Negativo {
+			guards = append(guards, fmt.Sprintf("%s >= 0", expr))
+		}
+	case migrator.TypeUint64:
+		if t.Max > 0 {
+			guards = append(guards, fmt.Sprintf("%s <= %d", expr, t.Max))
+		}
+		guards = append(guards, fmt.Sprintf("%s >= 0", expr))
+	case migrator.TypeFloat64:
+		if t.Menor != 0 {
+			guards = append(guards, fmt.Sprintf("%s > %v", expr, t.Menor))
+		}
+		if t.Mayor != 0 {
+			guards = append(guards, fmt.Sprintf("%s < %v", expr, t.Mayor))
+		}
+		if !t.Negativo {
+			guards = append(guards, fmt.Sprintf("%s >= 0", expr))
+		}
+	}
+	return guards
+}
+
+// conditionsKey describe el registro afectado con las condiciones de igualdad sobre la llave primaria (por ejemplo `id=10`)
+func conditionsKey(schemas []migrator.Fields, conditions []migrator.Where) string {
+	var keys []string
+	for _, c := range conditions {
+		if c.Condition != "=" {
+			continue
+		}
+		idx := slices.IndexFunc(schemas, func(f migrator.Fields) bool { return f.Name == c.Field })
+		if idx >= 0 && schemas[idx].PrimaryKey {
+			keys = append(keys, fmt.Sprintf("%s=%v", c.Field, c.Value))
+		}
+	}
+	if len(keys) == 0 {
+		for _, c := range conditions {
+			keys = append(keys, fmt.Sprintf("%s %s %v", c.Field, c.Condition, c.Value))
+		}
 	}
+	return strings.Join(keys, ", ")
 }
@@ -0,0 +1,127 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/deybin/pgorm/internal/adapters"
+	"github.com/deybin/pgorm/internal/core/builder"
+	"github.com/deybin/pgorm/logger"
+	"github.com/deybin/pgorm/migrator"
+)
+
+type stockItem struct {
+	Id     string `validate:"primaryKey"`
+	Nombre string `validate:"update"`
+	Stock  int64  `validate:"update;sum" validateType:"max=1000"`
+	Code   string `validate:"where"`
+}
+
+func (e stockItem) Name() string      { return "stock_items" }
+func (e stockItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e stockItem) Values() []any     { return migrator.EntityValues(e) }
+
+type versionItem struct {
+	Id      string  `validate:"primaryKey"`
+	Price   float64 `validate:"update"`
+	Nombre  string  `validate:"update"`
+	Code    string
+	Version int64 `validate:"version"`
+}
+
+func (e versionItem) Name() string      { return "version_items" }
+func (e versionItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e versionItem) Values() []any     { return migrator.EntityValues(e) }
+
+type stampedItem struct {
+	Id         string  `validate:"primaryKey"`
+	Total      float64 `validate:"update"`
+	Updated_at int64   `validate:"autoUpdateTime=milli"`
+}
+
+func (e stampedItem) Name() string      { return "stamped_items" }
+func (e stampedItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e stampedItem) Values() []any     { return migrator.EntityValues(e) }
+
+func whereId(id string) []migrator.Where {
+	return []migrator.Where{{Clause: "WHERE", Condition: "=", Field: "id", Value: id}}
+}
+
+func TestUpdate_Arithmetic(t *testing.T) {
+	ts := transaction(schema[stockItem]{}, adapters.UPDATE, migrator.EntityUpdate{
+		Entity: stockItem{Nombre: "Teclado", Stock: 5},
+		Conditions: []migrator.Where{
+			{Clause: "WHERE", Condition: "=", Field: "id", Value: "1"},
+			{Clause: "OR", Condition: "=", Field: "code", Value: "T-1"},
+		},
+	})
+	if err := builder.BuilderUpdateGeneric(ts); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	query := ts.Query()[0]
+	expected := "UPDATE stock_items SET stock= stock + $1, nombre= $2 WHERE (id = $3 OR code = $4) AND stock + $1 <= 1000 AND stock + $1 >= 0"
+	if query.Querys != expected {
+		t.Errorf("query inesperado: %q", query.Querys)
+	}
+	if len(query.Values) != 4 || query.Values[0] != int64(5) {
+		t.Errorf("valores inesperados: %v", query.Values)
+	}
+}
+
+func TestUpdate_Version(t *testing.T) {
+	ts := transaction(schema[versionItem]{}, adapters.UPDATE, migrator.EntityUpdate{Entity: versionItem{Price: 15, Version: 3}, Conditions: whereId("1")})
+	if err := builder.BuilderUpdateGeneric(ts); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	query := ts.Query()[0]
+	expected := "UPDATE version_items SET price= $1, version= version + 1 WHERE (id = $2) AND version = $3"
+	if query.Querys != expected {
+		t.Errorf("query inesperado: %q", query.Querys)
+	}
+	if !query.Version || query.Key != "id=1" || query.Values[2] != int64(3) {
+		t.Errorf("control de versión inesperado: %+v", query)
+	}
+}
+
+func TestUpdate_ExplicitZero(t *testing.T) {
+	ts := transaction(schema[versionItem]{}, adapters.UPDATE, migrator.EntityUpdate{Entity: versionItem{}, Conditions: whereId("1"), Set: []string{"price"}})
+	if err := builder.BuilderUpdateGeneric(ts); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	query := ts.Query()[0]
+	if query.Querys != "UPDATE version_items SET price= $1, version= version + 1 WHERE (id = $2) AND version = $3" || query.Values[0] != float64(0) {
+		t.Errorf("query inesperado: %q %v", query.Querys, query.Values)
+	}
+
+	// Set usa el nombre de la columna y solo acepta campos que se pueden actualizar
+	for _, set := range []string{"Nombre", "code"} {
+		ts := transaction(schema[versionItem]{}, adapters.UPDATE, migrator.EntityUpdate{Entity: versionItem{}, Conditions: whereId("1"), Set: []string{set}})
+		if err := builder.BuilderUpdateGeneric(ts); err == nil {
+			t.Errorf("se esperaba un error para Set %s", set)
+		}
+	}
+}
+
+func TestUpdate_AutoUpdateTime(t *testing.T) {
+	frozen := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	migrator.SetClock(func() time.Time { return frozen })
+	defer migrator.SetClock(nil)
+
+	ts := transaction(schema[stampedItem]{}, adapters.UPDATE, migrator.EntityUpdate{Entity: stampedItem{Total: 150}, Conditions: whereId("1")})
+	if err := builder.BuilderUpdateGeneric(ts); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	query := ts.Query()[0]
+	if query.Querys != "UPDATE stamped_items SET total= $1, updated_at= $2 WHERE id = $3" || query.Values[1] != frozen.UnixMilli() {
+		t.Errorf("query inesperado: %q %v", query.Querys, query.Values)
+	}
+}
+
+func TestUpdate_EmptyDataLanguage(t *testing.T) {
+	ts := transaction(schema[stockItem]{}, adapters.UPDATE)
+	ts.SetContext(logger.WithLanguage(context.Background(), logger.EN))
+	if err := builder.BuilderUpdateGeneric(ts); err == nil || err.Error() != "there is no data to update" {
+		t.Errorf("se esperaba el mensaje del catalogo en el idioma del contexto: %v", err)
+	}
+}
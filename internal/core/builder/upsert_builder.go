@@ -0,0 +1,106 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/deybin/pgorm/internal/adapters"
+	"github.com/deybin/pgorm/internal/core/domain"
+	"github.com/deybin/pgorm/migrator"
+	"github.com/jackc/pgx/v5"
+)
+
+func BuilderUpsertGeneric(ts *domain.Transactions, opts migrator.UpsertOptions) error {
+	if err := checkAudit(ts); err != nil {
+		return err
+	}
+	table := ts.Schema().Table().Name()
+	data := ts.Datos()
+	schema := ts.Schema().ParseInsert()
+	length := len(data)
+	if length > 0 {
+		conflict, target, err := upsertTarget(schema, opts)
+		if err != nil {
+			return err
+		}
+
+		var sqlExec = make([]adapters.DataExec, 0)
+		var data_insert []map[string]any
+
+		for _, item := range data {
+			preArray, err := migrator.CheckInsertGenericContext(ts.Context(), schema, item)
+			if err != nil {
+				return err
+			}
+			data_insert = append(data_insert, preArray)
+
+			var column []string
+			var values []string
+			var setters []string
+			var i int
+			var valuesExec []any
+			char := "$"
+			for _, k := range slices.Sorted(maps.Keys(preArray)) {
+				i++
+				column = append(column, k)
+				values = append(values, fmt.Sprintf("%s%d", char, i))
+				valuesExec = append(valuesExec, preArray[k])
+
+				if slices.Contains(target, k) {
+					continue
+				}
+				if slices.ContainsFunc(schema, func(f migrator.Fields) bool { return f.Name == k && (f.Update || f.AutoUpdateTime) }) {
+					setters = append(setters, fmt.Sprintf("%s = EXCLUDED.%s", k, k))
+				}
+			}
+
+			action := "DO NOTHING"
+			if !opts.DoNothing && len(setters) > 0 {
+				action = "DO UPDATE SET " + strings.Join(setters, ", ")
+			}
+
+			sqlPreparate := fmt.Sprintf("INSERT INTO %s (%s) VALUES(%s) ON CONFLICT %s %s%s", table, strings.Join(column, ", "), strings.Join(values, ", "), conflict, action, returning(ts))
+			sqlExec = append(sqlExec, adapters.DataExec{
+				Querys:    sqlPreparate,
+				Values:    valuesExec,
+				Action:    ts.Action(),
+				Table:     table,
+				Returning: isReturning(ts),
+				Audit:     auditUpsertExec(ts, schema, target, preArray, action != "DO NOTHING"),
+			})
+		}
+		ts.SetQuery(sqlExec)
+		ts.SetData(data_insert)
+		return nil
+	} else {
+		return messageError(ts, "data.insert")
+	}
+}
+
+// upsertTarget devuelve el destino del ON CONFLICT y las columnas que lo forman (estas no se actualizan),
+// las columnas deben existir en el esquema y el nombre de la restricción se escribe como identificador entre comillas
+func upsertTarget(schema []migrator.Fields, opts migrator.UpsertOptions) (string, []string, error) {
+	if opts.Constraint != "" {
+		return "ON CONSTRAINT " + pgx.Identifier{opts.Constraint}.Sanitize(), nil, nil
+	}
+	for _, column := range opts.Columns {
+		if !slices.ContainsFunc(schema, func(f migrator.Fields) bool { return f.Name == column }) {
+			return "", nil, fmt.Errorf("la columna %s del conflicto no existe en el esquema", column)
+		}
+	}
+	target := opts.Columns
+	if len(target) == 0 {
+		for _, f := range schema {
+			if f.PrimaryKey {
+				target = append(target, f.Name)
+			}
+		}
+	}
+	if len(target) == 0 {
+		return "", nil, errors.New("no se pudo determinar las columnas del conflicto, el esquema no tiene llave primaria")
+	}
+	return "(" + strings.Join(target, ", ") + ")", target, nil
+}
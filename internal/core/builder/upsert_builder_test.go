@@ -0,0 +1,66 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/deybin/pgorm/internal/adapters"
+	"github.com/deybin/pgorm/internal/core/builder"
+	"github.com/deybin/pgorm/migrator"
+)
+
+type upsertItem struct {
+	Id     string  `validate:"primaryKey;required"`
+	Nombre string  `validate:"required;update"`
+	Price  float64 `validate:"update"`
+	Code   string
+}
+
+func (e upsertItem) Name() string      { return "upsert_items" }
+func (e upsertItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e upsertItem) Values() []any     { return migrator.EntityValues(e) }
+
+func TestUpsert(t *testing.T) {
+	tests := []struct {
+		item     upsertItem
+		opts     migrator.UpsertOptions
+		expected string
+	}{
+		{upsertItem{Id: "1", Nombre: "teclado", Price: 20.5, Code: "T-1"}, migrator.UpsertOptions{},
+			"INSERT INTO upsert_items (code, id, nombre, price) VALUES($1, $2, $3, $4) ON CONFLICT (id) DO UPDATE SET nombre = EXCLUDED.nombre, price = EXCLUDED.price"},
+		{upsertItem{Id: "1", Nombre: "teclado"}, migrator.UpsertOptions{Columns: []string{"code"}},
+			"INSERT INTO upsert_items (id, nombre) VALUES($1, $2) ON CONFLICT (code) DO UPDATE SET nombre = EXCLUDED.nombre"},
+		{upsertItem{Id: "1", Nombre: "teclado"}, migrator.UpsertOptions{Constraint: "upsert_items_code_key", DoNothing: true},
+			`INSERT INTO upsert_items (id, nombre) VALUES($1, $2) ON CONFLICT ON CONSTRAINT "upsert_items_code_key" DO NOTHING`},
+	}
+	for _, tt := range tests {
+		ts := transaction(schema[upsertItem]{}, adapters.UPSERT, tt.item)
+		if err := builder.BuilderUpsertGeneric(ts, tt.opts); err != nil {
+			t.Errorf("no se esperaba este error: %v", err)
+			continue
+		}
+		if query := ts.Query()[0].Querys; query != tt.expected {
+			t.Errorf("query inesperado: %q", query)
+		}
+	}
+}
+
+func TestUpsert_InvalidTarget(t *testing.T) {
+	tests := []migrator.UpsertOptions{
+		{Columns: []string{"code; DROP TABLE upsert_items"}},
+		{Columns: []string{"Code"}},
+	}
+	for _, opts := range tests {
+		ts := transaction(schema[upsertItem]{}, adapters.UPSERT, upsertItem{Id: "1", Nombre: "teclado"})
+		if err := builder.BuilderUpsertGeneric(ts, opts); err == nil {
+			t.Errorf("se esperaba un error para las columnas %v", opts.Columns)
+		}
+	}
+
+	ts := transaction(schema[upsertItem]{}, adapters.UPSERT, upsertItem{Id: "1", Nombre: "teclado"})
+	if err := builder.BuilderUpsertGeneric(ts, migrator.UpsertOptions{Constraint: `key" DO NOTHING; --`, DoNothing: true}); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	if query := ts.Query()[0].Querys; query != `INSERT INTO upsert_items (id, nombre) VALUES($1, $2) ON CONFLICT ON CONSTRAINT "key"" DO NOTHING; --" DO NOTHING` {
+		t.Errorf("la restricción debe escribirse entre comillas: %q", query)
+	}
+}
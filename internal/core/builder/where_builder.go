@@ -0,0 +1,79 @@
+package builder
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/deybin/pgorm/internal/core/clause"
+	"github.com/deybin/pgorm/migrator"
+)
+
+/*
+whereConditions genera las condiciones de la cláusula WHERE (sin la palabra WHERE) de un update o delete.
+
+La primera condición no lleva su cláusula (WHERE), las siguientes anteponen su cláusula (AND, OR).
+Las condiciones ya fueron validadas por migrator.CheckWhereGeneric: IN usa un placeholder por valor, BETWEEN dos,
+IS NULL ninguno y = ANY envía la lista completa en un solo placeholder.
+Los placeholders continúan desde el valor de i, que se actualiza con el ultimo placeholder usado.
+
+	Return
+		- (string) condiciones, por ejemplo `id = $3 OR document = $4`
+		- ([]any) valores de los placeholders
+*/
+func whereConditions(conditions []migrator.Where, i *int) (string, []any) {
+	var wheres []string
+	var valuesExec []any
+	for k, v := range conditions {
+		var expr string
+		switch clause.OperatorWhere(v.Condition) {
+		case clause.IN, clause.NOT_IN:
+			list := reflect.ValueOf(v.Value)
+			placeholders := make([]string, list.Len())
+			for j := range placeholders {
+				*i++
+				placeholders[j] = fmt.Sprintf("$%d", *i)
+				valuesExec = append(valuesExec, list.Index(j).Interface())
+			}
+			expr = fmt.Sprintf("%s %s (%s)", v.Field, v.Condition, strings.Join(placeholders, ", "))
+		case clause.BETWEEN, clause.NOT_BETWEEN:
+			list := reflect.ValueOf(v.Value)
+			expr = fmt.Sprintf("%s %s $%d AND $%d", v.Field, v.Condition, *i+1, *i+2)
+			*i += 2
+			valuesExec = append(valuesExec, list.Index(0).Interface(), list.Index(1).Interface())
+		case clause.IS_NULL, clause.IS_NOT_NULL:
+			expr = fmt.Sprintf("%s %s", v.Field, v.Condition)
+		case clause.ANY:
+			*i++
+			expr = fmt.Sprintf("%s %s($%d)", v.Field, v.Condition, *i)
+			valuesExec = append(valuesExec, v.Value)
+		default:
+			*i++
+			expr = fmt.Sprintf("%s %s $%d", v.Field, v.Condition, *i)
+			valuesExec = append(valuesExec, v.Value)
+		}
+		if k > 0 {
+			expr = fmt.Sprintf("%s %s", v.Clause, expr)
+		}
+		wheres = append(wheres, expr)
+	}
+	return strings.Join(wheres, " "), valuesExec
+}
+
+/*
+whereClause une las condiciones del usuario con las condiciones de control (guards) generadas por el builder.
+
+Las condiciones del usuario se agrupan entre paréntesis para que un OR no anule las condiciones de control.
+*/
+func whereClause(conditions string, guards []string) string {
+	if len(guards) == 0 {
+		if conditions == "" {
+			return ""
+		}
+		return "WHERE " + conditions
+	}
+	if conditions == "" {
+		return "WHERE " + strings.Join(guards, " AND ")
+	}
+	return fmt.Sprintf("WHERE (%s) AND %s", conditions, strings.Join(guards, " AND "))
+}
@@ -0,0 +1,57 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/deybin/pgorm/internal/adapters"
+	"github.com/deybin/pgorm/internal/core/builder"
+	"github.com/deybin/pgorm/migrator"
+)
+
+type whereItem struct {
+	Id   string `validate:"primaryKey"`
+	Code string `validate:"where"`
+}
+
+func (e whereItem) Name() string      { return "where_items" }
+func (e whereItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e whereItem) Values() []any     { return migrator.EntityValues(e) }
+
+func TestWhere_Operators(t *testing.T) {
+	ts := transaction(schema[whereItem]{}, adapters.DELETE)
+	err := builder.BuilderDeleteGeneric(ts, []migrator.Where{
+		{Clause: "WHERE", Condition: "in", Field: "id", Value: []string{"1", "2"}},
+		{Clause: "AND", Condition: "between", Field: "code", Value: []any{"A", "M"}},
+		{Clause: "OR", Condition: "is null", Field: "code"},
+		{Clause: "OR", Condition: "= ANY", Field: "id", Value: []string{"3", "4"}},
+	})
+	if err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	query := ts.Query()[0]
+	expected := "DELETE FROM where_items WHERE id IN ($1, $2) AND code BETWEEN $3 AND $4 OR code IS NULL OR id = ANY($5)"
+	if query.Querys != expected {
+		t.Errorf("query inesperado: %q", query.Querys)
+	}
+	if len(query.Values) != 5 {
+		t.Errorf("valores inesperados: %v", query.Values)
+	}
+
+	// la cláusula de la primera condición no se escribe y puede omitirse
+	ts = transaction(schema[whereItem]{}, adapters.DELETE)
+	if err := builder.BuilderDeleteGeneric(ts, []migrator.Where{{Condition: "=", Field: "id", Value: "1"}}); err != nil || ts.Query()[0].Querys != "DELETE FROM where_items WHERE id = $1" {
+		t.Errorf("se esperaba aceptar la primera condición sin cláusula: %v %+v", err, ts.Query())
+	}
+
+	invalid := [][]migrator.Where{
+		{{Clause: "WHERE", Condition: "= 1; DROP TABLE where_items; --", Field: "id", Value: "1"}},
+		{{Clause: "WHERE", Condition: "BETWEEN", Field: "id", Value: []any{"1"}}},
+		{{Condition: "=", Field: "id", Value: "1"}, {Condition: "=", Field: "code", Value: "A"}},
+		{{Clause: "WHERE", Condition: "=", Field: "id", Value: "1"}, {Clause: "WHERE", Condition: "=", Field: "code", Value: "A"}},
+	}
+	for _, where := range invalid {
+		if err := builder.BuilderDeleteGeneric(transaction(schema[whereItem]{}, adapters.DELETE), where); err == nil {
+			t.Errorf("se esperaba un error para %+v", where)
+		}
+	}
+}
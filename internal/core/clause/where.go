@@ -32,6 +32,9 @@ const (
 	NOT_IN      OperatorWhere = "NOT IN"
 	BETWEEN     OperatorWhere = "BETWEEN"
 	NOT_BETWEEN OperatorWhere = "NOT BETWEEN"
+	IS_NULL     OperatorWhere = "IS NULL"
+	IS_NOT_NULL OperatorWhere = "IS NOT NULL"
+	ANY         OperatorWhere = "= ANY"
 )
 
 // Where where clause
@@ -139,6 +142,19 @@ func (w *Where) buildExp(expr ExpressionFilter) (string, error) {
 		// argString += fmt.Sprintf("$%d", q.argsLen)
 		w.Arguments = append(w.Arguments, expr.Args.([]interface{})[1])
 		w.ArgumentsLen++
+	case IS_NULL, IS_NOT_NULL:
+		SQL.WriteString(expr.Column)
+		SQL.WriteByte(' ')
+		SQL.WriteString(string(expr.Operators))
+	case ANY:
+		SQL.WriteString(expr.Column)
+		SQL.WriteByte(' ')
+		SQL.WriteString(string(expr.Operators))
+		SQL.WriteString("($")
+		SQL.WriteString(strconv.Itoa(w.ArgumentsLen))
+		SQL.WriteByte(')')
+		w.Arguments = append(w.Arguments, expr.Args)
+		w.ArgumentsLen++
 	default:
 		SQL.WriteString(expr.Column)
 		SQL.WriteByte(' ')
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"github.com/deybin/pgorm/internal/core/clause"
+	"github.com/deybin/pgorm/migrator"
 )
 
 /** guarda la estructura de consulta sql, aparir de aquí se generar la consulta sql */
@@ -15,10 +16,23 @@ type Sintaxis struct {
 	GroupBy_field       clause.GroupBy
 	ArgsLen_field       int
 	Args_field          []any
-	QueryFull_field     string /** guarda la consulta sql directa en string */
-	WorkQueryFull_field bool   /** establece si se va a utilizar una consulta directa mediante queryFull o mediante la estructura true:= se considerara queryFull false:= se considerara  estructura para formar la consulta sql*/
+	QueryFull_field     string          /** guarda la consulta sql directa en string */
+	WorkQueryFull_field bool            /** establece si se va a utilizar una consulta directa mediante queryFull o mediante la estructura true:= se considerara queryFull false:= se considerara  estructura para formar la consulta sql*/
+	Nested_field        bool            /** establece si el resultado se mapea hacia structs anidados (JOIN con prefijos) en lugar del escaneo plano*/
+	Model_field         migrator.Schema /** esquema de la tabla principal, necesario para precargar sus relaciones*/
+	Preload_field       []string        /** relaciones que se cargaran después de la consulta principal*/
+	Deleted_field       DeletedScope    /** registros eliminados lógicamente que se incluyen cuando el modelo usa softDelete*/
 }
 
+// DeletedScope indica que registros eliminados lógicamente (softDelete) se consultan
+type DeletedScope uint8
+
+const (
+	ExcludeDeleted DeletedScope = iota // solo registros no eliminados (por defecto)
+	IncludeDeleted                     // todos los registros
+	OnlyDeleted                        // solo registros eliminados
+)
+
 func NewSintaxis() *Sintaxis {
 	return &Sintaxis{}
 }
@@ -60,6 +74,10 @@ Esta función define el nombre de la tabla principal sobre la cual se realizará
 las operaciones SQL (SELECT, JOIN, WHERE, etc.). Es esencial establecer esta propiedad
 antes de construir la consulta.
 
+From no conoce el esquema de la tabla, por eso no filtra los registros eliminados lógicamente (softDelete);
+para aplicar el filtro la tabla se establece con Model, y si se necesita un alias se llama a From después de Model
+con el mismo nombre de tabla, por ejemplo Model(&ProductsSchema{}).From("products p") filtra con `p.deleted_at IS NULL`.
+
 Ejemplo de uso:
 
 	queryBuilder := new(pgorm.Query).New(pgorm.QConfig{Database: "my_database"})
@@ -314,6 +332,101 @@ func (q *Sintaxis) Join(tp clause.TypeJoin, table string, on string) *Sintaxis {
 	return q
 }
 
+/*
+Nested activa el mapeo anidado del resultado de la consulta.
+
+Con este modo las columnas con prefijo (por ejemplo `customer.name` o `customer_name` según la etiqueta `prefix`)
+se asignan a los structs anidados del destino y las filas repetidas por un JOIN uno a muchos se agrupan
+en el padre según su llave primaria, acumulando los hijos en sus slices.
+
+Ejemplo de uso:
+
+	query := pgorm.NewQuery().From("orders o").
+		Select(`o.id`, `c.name AS "customer.name"`, `l.id AS "lines.id"`, `l.amount AS "lines.amount"`).
+		Join(pgorm.INNER, "customers c", "c.id = o.customer_id").
+		Join(pgorm.LEFT, "order_lines l", "l.order_id = o.id").
+		Nested()
+
+	orders, err := pgorm.ExecQuery[[]Order](db, ctx, query)
+
+Devuelve:
+  - Un puntero al struct Query actualizado para permitir el encadenamiento de métodos.
+*/
+func (q *Sintaxis) Nested() *Sintaxis {
+	q.Nested_field = true
+	return q
+}
+
+/*
+Model establece la tabla principal de la consulta a partir de su esquema.
+
+Además de definir la cláusula FROM con el nombre de la tabla, guarda el esquema para poder
+precargar las relaciones que declara (ver Preload).
+
+Ejemplo de uso:
+
+	query := pgorm.NewQuery().Model(&tables.OrdersSchema{}).Select().Preload("Lines")
+
+Parámetros:
+  - s (migrator.Schema): Esquema de la tabla principal.
+
+Devuelve:
+  - Un puntero al struct Query actualizado para permitir el encadenamiento de métodos.
+*/
+func (q *Sintaxis) Model(s migrator.Schema) *Sintaxis {
+	q.Model_field = s
+	q.From_field.Table = s.Table().Name()
+	return q
+}
+
+/*
+Preload indica las relaciones que se cargaran después de ejecutar la consulta principal.
+
+Las relaciones deben estar declaradas por el esquema establecido con Model (interfaz migrator.Relations).
+Por cada relación se ejecuta una sola consulta `= ANY($1)` con las llaves de todos los registros obtenidos,
+y el resultado se asigna al campo del struct destino que tiene el mismo nombre que la relación.
+
+Ejemplo de uso:
+
+	orders, err := pgorm.ExecQuery[[]Order](db, ctx, pgorm.NewQuery().Model(&OrdersSchema{}).Select().Preload("Lines", "Customer"))
+
+Parámetros:
+  - relations (...string): Nombres de las relaciones a cargar.
+
+Devuelve:
+  - Un puntero al struct Query actualizado para permitir el encadenamiento de métodos.
+*/
+func (q *Sintaxis) Preload(relations ...string) *Sintaxis {
+	q.Preload_field = append(q.Preload_field, relations...)
+	return q
+}
+
+/*
+WithDeleted incluye en la consulta los registros eliminados lógicamente.
+
+Solo tiene efecto cuando la tabla se establece con Model y su esquema tiene un campo `validate:"softDelete"`,
+en ese caso por defecto se agrega la condición `tabla.campo IS NULL` (con el alias de la tabla si se indico en From)
+y las relaciones precargadas con Preload también excluyen sus registros eliminados.
+
+Devuelve:
+  - Un puntero al struct Query actualizado para permitir el encadenamiento de métodos.
+*/
+func (q *Sintaxis) WithDeleted() *Sintaxis {
+	q.Deleted_field = IncludeDeleted
+	return q
+}
+
+/*
+OnlyDeleted consulta solo los registros eliminados lógicamente (`tabla.campo IS NOT NULL`).
+
+Devuelve:
+  - Un puntero al struct Query actualizado para permitir el encadenamiento de métodos.
+*/
+func (q *Sintaxis) OnlyDeleted() *Sintaxis {
+	q.Deleted_field = OnlyDeleted
+	return q
+}
+
 /*
 Reset reinicia la configuración de la consulta SQL en el struct Query.
 
@@ -341,6 +454,10 @@ func (q *Sintaxis) Reset() {
 	q.Args_field = []any{}
 	q.QueryFull_field = ""
 	q.WorkQueryFull_field = false
+	q.Nested_field = false
+	q.Model_field = nil
+	q.Preload_field = nil
+	q.Deleted_field = ExcludeDeleted
 }
 func (q *Sintaxis) Arguments() []any {
 	return q.Args_field
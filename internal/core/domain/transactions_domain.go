@@ -1,8 +1,12 @@
 package domain
 
 import (
+	"context"
+	"strings"
+
 	"github.com/deybin/pgorm/internal/adapters"
 	"github.com/deybin/pgorm/migrator"
+	"github.com/jackc/pgx/v5"
 )
 
 // type Actions string
@@ -15,12 +19,16 @@ import (
 // )
 
 type Transactions struct {
-	ob       []migrator.Entity //datos para observación
-	data     []map[string]any  //datos para insertar o actualizar o eliminar
-	dataExec []adapters.DataExec
-	schema   migrator.Schema
-	action   adapters.Actions
-	errors   []string
+	ob        []migrator.Entity //datos para observación
+	data      []map[string]any  //datos para insertar o actualizar o eliminar
+	dataExec  []adapters.DataExec
+	schema    migrator.Schema
+	action    adapters.Actions
+	returning string          //columnas de la cláusula RETURNING, vació si no se solicita
+	expect    bool            //las sentencias de update/delete deben afectar al menos un registro
+	maxRows   int64           //máximo de registros que puede afectar cada sentencia de update/delete, 0 sin limite
+	ctx       context.Context //contexto de los hooks de ciclo de vida
+	errors    []string
 }
 
 func NewTransaction(s migrator.Schema, datos ...migrator.Entity) Transactions {
@@ -64,4 +72,43 @@ func (t *Transactions) SetAction(action adapters.Actions) {
 	t.action = action
 }
 
+func (t Transactions) Returning() string {
+	return t.returning
+}
+
+// SetReturning establece las columnas de la cláusula RETURNING, sin columnas se retornan todas (*); cada columna se escribe como identificador entre comillas
+func (t *Transactions) SetReturning(columns ...string) {
+	if len(columns) == 0 {
+		t.returning = "*"
+		return
+	}
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = pgx.Identifier{column}.Sanitize()
+	}
+	t.returning = strings.Join(quoted, ", ")
+}
+
+// Expect indica si las sentencias de update/delete deben afectar registros y el máximo permitido (0 sin limite)
+func (t Transactions) Expect() (bool, int64) {
+	return t.expect, t.maxRows
+}
+
+func (t *Transactions) SetExpect(maxRows int64) {
+	t.expect = true
+	t.maxRows = maxRows
+}
+
+// Context devuelve el contexto establecido para los hooks, context.Background() si no se estableció
+func (t Transactions) Context() context.Context {
+	if t.ctx == nil {
+		return context.Background()
+	}
+	return t.ctx
+}
+
+func (t *Transactions) SetContext(ctx context.Context) {
+	t.ctx = ctx
+}
+
 /*******************************Crud Transactions************************************/
@@ -0,0 +1,546 @@
+package mapper
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+plan guarda cómo se relaciona un struct con las columnas planas de un resultado.
+
+  - scalars: campos simples (string, int, time.Time, ...) y la columna de la que se leen.
+  - ones: structs anidados (uno a uno), se llenan con las columnas que comparten su prefijo.
+  - manys: slices de structs (uno a muchos), se agrupan por la llave primaria del hijo.
+  - keys: columnas que identifican un registro (campos `validate:"primaryKey"` o la columna `id`).
+*/
+type plan struct {
+	typ     reflect.Type
+	scalars []scalarField
+	ones    []nestedField
+	manys   []nestedField
+	keys    []string
+}
+
+type scalarField struct {
+	index  []int
+	column string
+}
+
+type nestedField struct {
+	index []int
+	plan  *plan
+	ptr   bool // el campo (o el elemento del slice) es un puntero al struct
+}
+
+// record es una instancia en construcción, los structs anidados y los hijos se acumulan aparte para no perder referencias al crecer los slices.
+type record struct {
+	value    reflect.Value // puntero al struct
+	ones     []*record     // structs anidados (uno a uno), nil si la fila no los trae
+	children [][]*record
+	position []map[string]int
+}
+
+var plans sync.Map
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	uuidType = reflect.TypeOf(uuid.UUID{})
+)
+
+/*
+Nested mapea filas planas (por ejemplo el resultado de un JOIN) hacia structs anidados.
+
+Las columnas se relacionan con los campos mediante la etiqueta `db` (o el nombre del campo en minúsculas).
+Un campo de tipo struct o slice de structs toma las columnas que comienzan con su prefijo:
+
+  - por defecto el prefijo es el nombre de la columna del campo seguido de un punto: `db:"customer"` ⇒ `customer.name`.
+  - con la etiqueta `prefix` se usa el prefijo indicado tal cual: `prefix:"customer_"` ⇒ `customer_name`.
+  - un struct embebido sin etiquetas comparte las columnas del padre.
+
+Los modelos con referencias circulares (`Employee{Manager *Employee}` o `Order{Customer}` / `Customer{Orders []Order}`)
+no se recorren indefinidamente: un tipo que ya esta en el camino desde la raíz solo se mapea una vez más si el campo
+tiene la etiqueta `prefix`, y dentro de él no se vuelve a descender; en otro caso el campo se deja en su valor cero.
+
+Las filas que comparten la llave primaria del padre se agrupan en un solo registro y los structs de los slices
+(uno a muchos) se acumulan sin repetirse según su propia llave primaria.
+
+Ejemplo de uso:
+
+	type Order struct {
+		Id       string `db:"id" validate:"primaryKey"`
+		Customer Customer `db:"customer"`
+		Lines    []Line `db:"lines"`
+	}
+
+	rows := []map[string]any{{"id": "1", "customer.name": "ana", "lines.id": 10, "lines.amount": 2.5}}
+	var orders []Order
+	err := mapper.Nested(rows, &orders)
+
+Parámetros:
+  - rows ([]map[string]any): filas devueltas por la consulta, la llave es el nombre (alias) de la columna.
+  - dest (any): puntero a un struct o a un slice de structs (o punteros a structs).
+
+Retorna:
+  - Un error si dest no es válido o si algún valor no puede asignarse al campo destino.
+*/
+func Nested(rows []map[string]any, dest any) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("el destino debe ser un puntero")
+	}
+	target := rv.Elem()
+
+	isSlice := target.Kind() == reflect.Slice
+	elemType := target.Type()
+	if isSlice {
+		elemType = elemType.Elem()
+	}
+	elemPtr := elemType.Kind() == reflect.Ptr
+	if elemPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("tipo de dato no soportado para mapeo anidado: %s", elemType)
+	}
+
+	p := planOf(elemType)
+	var records []*record
+	position := map[string]int{}
+
+	for i, row := range rows {
+		key, ok := p.key(row)
+		if !ok {
+			key = strconv.Itoa(i)
+		}
+		pos, exists := position[key]
+		if !exists {
+			rec, err := p.newRecord(row)
+			if err != nil {
+				return err
+			}
+			records = append(records, rec)
+			pos = len(records) - 1
+			position[key] = pos
+		}
+		if err := p.collect(records[pos], row); err != nil {
+			return err
+		}
+	}
+
+	if !isSlice {
+		if len(records) == 0 {
+			return fmt.Errorf("not found information")
+		}
+		item := records[0].materialize(p)
+		if elemPtr {
+			ptr := reflect.New(elemType)
+			ptr.Elem().Set(item)
+			item = ptr
+		}
+		target.Set(item)
+		return nil
+	}
+
+	out := reflect.MakeSlice(target.Type(), 0, len(records))
+	for _, rec := range records {
+		item := rec.materialize(p)
+		if elemPtr {
+			ptr := reflect.New(elemType)
+			ptr.Elem().Set(item)
+			item = ptr
+		}
+		out = reflect.Append(out, item)
+	}
+	target.Set(out)
+	return nil
+}
+
+/*
+Assign copia un valor devuelto por la base de datos en el campo indicado, realizando las conversiones habituales
+(enteros de distinto tamaño, punteros, valores de pgtype que implementan driver.Valuer, números y uuid recibidos como texto).
+*/
+func Assign(field reflect.Value, val any) error {
+	if val == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	if field.Kind() == reflect.Ptr {
+		if reflect.TypeOf(val).AssignableTo(field.Type()) {
+			field.Set(reflect.ValueOf(val))
+			return nil
+		}
+		elem := reflect.New(field.Type().Elem())
+		if err := Assign(elem.Elem(), val); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	rv := reflect.ValueOf(val)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+		return Assign(field, rv.Elem().Interface())
+	}
+
+	if rv.Type().AssignableTo(field.Type()) {
+		field.Set(rv)
+		return nil
+	}
+
+	// normalizeRow devuelve los uuid como texto
+	if field.Type() == uuidType && rv.Kind() == reflect.String {
+		id, err := uuid.Parse(rv.String())
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(id))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		switch rv.Kind() {
+		case reflect.String:
+			field.SetString(rv.String())
+			return nil
+		case reflect.Slice:
+			if b, ok := val.([]byte); ok {
+				field.SetString(string(b))
+				return nil
+			}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			field.Set(rv.Convert(field.Type()))
+			return nil
+		case reflect.String:
+			number, err := strconv.ParseFloat(rv.String(), 64)
+			if err != nil {
+				return err
+			}
+			field.Set(reflect.ValueOf(number).Convert(field.Type()))
+			return nil
+		}
+	case reflect.Bool:
+		if rv.Kind() == reflect.Bool {
+			field.SetBool(rv.Bool())
+			return nil
+		}
+	}
+
+	if valuer, ok := val.(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return err
+		}
+		return Assign(field, value)
+	}
+
+	if rv.Type().ConvertibleTo(field.Type()) && rv.Kind() == field.Kind() {
+		field.Set(rv.Convert(field.Type()))
+		return nil
+	}
+
+	return fmt.Errorf("no se puede asignar %T al campo de tipo %s", val, field.Type())
+}
+
+/*
+ColumnName devuelve el nombre de columna asociado a un campo: la etiqueta `db` o el nombre del campo en minúsculas.
+*/
+func ColumnName(field reflect.StructField) string {
+	name := field.Tag.Get("db")
+	if name == "" {
+		name = strings.ToLower(field.Name)
+	}
+	return name
+}
+
+/*
+FieldByColumn busca dentro de un struct el campo cuyo nombre de columna coincide con column.
+*/
+func FieldByColumn(v reflect.Value, column string) (reflect.Value, bool) {
+	v = reflect.Indirect(v)
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && field.Tag.Get("db") == "" && field.Tag.Get("prefix") == "" {
+			if found, ok := FieldByColumn(v.Field(i), column); ok {
+				return found, true
+			}
+			continue
+		}
+		if ColumnName(field) == column {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func planOf(t reflect.Type) *plan {
+	if cached, ok := plans.Load(t); ok {
+		return cached.(*plan)
+	}
+	p := subPlan(t, "", map[reflect.Type]int{})
+	plans.Store(t, p)
+	return p
+}
+
+// build arma el plan de t, path cuenta las veces que cada tipo aparece en el camino desde la raíz para cortar los ciclos
+func (p *plan) build(t reflect.Type, parent []int, prefix string, path map[reflect.Type]int) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+		index := append(append([]int{}, parent...), i)
+		column := ColumnName(field)
+
+		fieldType := field.Type
+		ptr := fieldType.Kind() == reflect.Ptr
+		if ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		switch {
+		case isNested(fieldType):
+			if field.Anonymous && tag == "" && field.Tag.Get("prefix") == "" && !ptr {
+				p.build(fieldType, index, prefix, path)
+				continue
+			}
+			if !descend(field, fieldType, path) {
+				continue
+			}
+			p.ones = append(p.ones, nestedField{index: index, plan: subPlan(fieldType, prefix+childPrefix(field, column), path), ptr: ptr})
+		case fieldType.Kind() == reflect.Slice && isNested(elemStruct(fieldType.Elem())):
+			elem := fieldType.Elem()
+			if !descend(field, elemStruct(elem), path) {
+				continue
+			}
+			p.manys = append(p.manys, nestedField{index: index, plan: subPlan(elemStruct(elem), prefix+childPrefix(field, column), path), ptr: elem.Kind() == reflect.Ptr})
+		default:
+			p.scalars = append(p.scalars, scalarField{index: index, column: prefix + column})
+			if hasPrimaryKey(field.Tag.Get("validate")) {
+				p.keys = append(p.keys, prefix+column)
+			}
+		}
+	}
+}
+
+func subPlan(t reflect.Type, prefix string, path map[reflect.Type]int) *plan {
+	path[t]++
+	defer func() { path[t]-- }()
+	p := &plan{typ: t}
+	p.build(t, nil, prefix, path)
+	if len(p.keys) == 0 {
+		for _, s := range p.scalars {
+			if s.column == prefix+"id" {
+				p.keys = append(p.keys, s.column)
+			}
+		}
+	}
+	return p
+}
+
+// descend indica si se puede mapear el struct t del campo: los tipos que ya están en el camino solo con la etiqueta prefix y una sola vez
+func descend(field reflect.StructField, t reflect.Type, path map[reflect.Type]int) bool {
+	switch path[t] {
+	case 0:
+		return true
+	case 1:
+		_, explicit := field.Tag.Lookup("prefix")
+		return explicit
+	}
+	return false
+}
+
+func childPrefix(field reflect.StructField, column string) string {
+	if prefix, ok := field.Tag.Lookup("prefix"); ok {
+		return prefix
+	}
+	return column + "."
+}
+
+func elemStruct(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+func isNested(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType
+}
+
+func hasPrimaryKey(tag string) bool {
+	for _, rule := range strings.FieldsFunc(tag, func(r rune) bool { return r == ';' || r == ',' }) {
+		if strings.TrimSpace(rule) == "primaryKey" {
+			return true
+		}
+	}
+	return false
+}
+
+// key arma la llave del registro a partir de sus columnas primarias; ok es falso si no tiene llave o todas son nulas.
+func (p *plan) key(row map[string]any) (string, bool) {
+	if len(p.keys) == 0 {
+		return "", false
+	}
+	parts := make([]string, len(p.keys))
+	empty := true
+	for i, column := range p.keys {
+		if v := row[column]; v != nil {
+			empty = false
+			parts[i] = fmt.Sprint(v)
+		}
+	}
+	if empty {
+		return "", false
+	}
+	return strings.Join(parts, "|"), true
+}
+
+// present indica si la fila contiene al menos un valor no nulo para el struct (descarta los LEFT JOIN sin coincidencia).
+func (p *plan) present(row map[string]any) bool {
+	for _, s := range p.scalars {
+		if row[s.column] != nil {
+			return true
+		}
+	}
+	for _, one := range p.ones {
+		if one.plan.present(row) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *plan) newRecord(row map[string]any) (*record, error) {
+	rec := &record{
+		value:    reflect.New(p.typ),
+		ones:     make([]*record, len(p.ones)),
+		children: make([][]*record, len(p.manys)),
+		position: make([]map[string]int, len(p.manys)),
+	}
+	for i := range p.manys {
+		rec.position[i] = map[string]int{}
+	}
+	if err := p.fill(rec.value.Elem(), row); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+func (p *plan) fill(v reflect.Value, row map[string]any) error {
+	for _, s := range p.scalars {
+		val, ok := row[s.column]
+		if !ok {
+			continue
+		}
+		if err := Assign(v.FieldByIndex(s.index), val); err != nil {
+			return fmt.Errorf("columna %s: %w", s.column, err)
+		}
+	}
+	return nil
+}
+
+// collect agrega a rec los structs anidados (uno a uno) y los hijos (uno a muchos) presentes en la fila.
+func (p *plan) collect(rec *record, row map[string]any) error {
+	for i, one := range p.ones {
+		if !one.plan.present(row) {
+			continue
+		}
+		if rec.ones[i] == nil {
+			child, err := one.plan.newRecord(row)
+			if err != nil {
+				return err
+			}
+			rec.ones[i] = child
+		}
+		if err := one.plan.collect(rec.ones[i], row); err != nil {
+			return err
+		}
+	}
+	for i, many := range p.manys {
+		if !many.plan.present(row) {
+			continue
+		}
+		key, ok := many.plan.key(row)
+		if !ok {
+			key = strconv.Itoa(len(rec.children[i]))
+		}
+		pos, exists := rec.position[i][key]
+		if !exists {
+			child, err := many.plan.newRecord(row)
+			if err != nil {
+				return err
+			}
+			rec.children[i] = append(rec.children[i], child)
+			pos = len(rec.children[i]) - 1
+			rec.position[i][key] = pos
+		}
+		if err := many.plan.collect(rec.children[i][pos], row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// materialize construye el valor final del struct asignando los structs anidados y los slices de hijos acumulados.
+func (rec *record) materialize(p *plan) reflect.Value {
+	v := rec.value.Elem()
+	for i, one := range p.ones {
+		if rec.ones[i] == nil {
+			continue
+		}
+		item := rec.ones[i].materialize(one.plan)
+		field := v.FieldByIndex(one.index)
+		if one.ptr {
+			field.Set(item.Addr())
+		} else {
+			field.Set(item)
+		}
+	}
+	for i, many := range p.manys {
+		field := v.FieldByIndex(many.index)
+		out := reflect.MakeSlice(field.Type(), 0, len(rec.children[i]))
+		for _, child := range rec.children[i] {
+			item := child.materialize(many.plan)
+			if many.ptr {
+				ptr := reflect.New(many.plan.typ)
+				ptr.Elem().Set(item)
+				item = ptr
+			}
+			out = reflect.Append(out, item)
+		}
+		field.Set(out)
+	}
+	return v
+}
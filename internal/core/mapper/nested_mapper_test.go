@@ -0,0 +1,84 @@
+package mapper
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type cycleCustomer struct {
+	Id     uuid.UUID    `db:"id" validate:"primaryKey"`
+	Name   string       `db:"name"`
+	Orders []cycleOrder `db:"orders"`
+}
+
+type cycleOrder struct {
+	Id       int64          `db:"id" validate:"primaryKey"`
+	Customer *cycleCustomer `db:"customer"`
+}
+
+type cycleEmployee struct {
+	Id      int64          `db:"id" validate:"primaryKey"`
+	Manager *cycleEmployee `prefix:"manager_"`
+}
+
+func TestNested_CircularTypes(t *testing.T) {
+	id := uuid.New()
+	rows := []map[string]any{
+		{"id": id.String(), "name": "ana", "orders.id": int64(1)},
+		{"id": id.String(), "name": "ana", "orders.id": int64(2)},
+	}
+	var customers []cycleCustomer
+	if err := Nested(rows, &customers); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	if len(customers) != 1 || customers[0].Id != id || len(customers[0].Orders) != 2 || customers[0].Orders[0].Customer != nil {
+		t.Errorf("resultado inesperado: %+v", customers)
+	}
+
+	var employee cycleEmployee
+	row := []map[string]any{{"id": int64(2), "manager_id": int64(1), "manager_manager_id": int64(0)}}
+	if err := Nested(row, &employee); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	if employee.Manager == nil || employee.Manager.Id != 1 || employee.Manager.Manager != nil {
+		t.Errorf("resultado inesperado: %+v", employee)
+	}
+}
+
+type oneAddress struct {
+	Id   int64  `db:"id" validate:"primaryKey"`
+	City string `db:"city"`
+}
+
+type oneCustomer struct {
+	Id        int64        `db:"id" validate:"primaryKey"`
+	Addresses []oneAddress `db:"addresses"`
+}
+
+type oneOrder struct {
+	Id       int64        `db:"id" validate:"primaryKey"`
+	Customer *oneCustomer `db:"customer"`
+	Billing  oneCustomer  `prefix:"billing_"`
+}
+
+func TestNested_ManyInsideOne(t *testing.T) {
+	rows := []map[string]any{
+		{"id": int64(1), "customer.id": int64(7), "customer.addresses.id": int64(10), "customer.addresses.city": "lima", "billing_id": int64(8), "billing_addresses.id": int64(30)},
+		{"id": int64(1), "customer.id": int64(7), "customer.addresses.id": int64(11), "customer.addresses.city": "cusco", "billing_id": int64(8), "billing_addresses.id": int64(30)},
+		{"id": int64(2), "customer.id": nil, "customer.addresses.id": nil, "customer.addresses.city": nil},
+	}
+	var orders []oneOrder
+	if err := Nested(rows, &orders); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	if len(orders) != 2 || orders[0].Customer == nil || len(orders[0].Customer.Addresses) != 2 || orders[0].Customer.Addresses[1].City != "cusco" {
+		t.Fatalf("los hijos del struct anidado deben acumularse: %+v", orders)
+	}
+	if orders[0].Billing.Id != 8 || len(orders[0].Billing.Addresses) != 1 {
+		t.Errorf("struct anidado por valor inesperado: %+v", orders[0].Billing)
+	}
+	if orders[1].Customer != nil {
+		t.Errorf("un LEFT JOIN sin coincidencia debe dejar nil: %+v", orders[1])
+	}
+}
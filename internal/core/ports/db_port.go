@@ -13,9 +13,9 @@ type DBPort interface {
 	ExecuteWithPgxScanAndSchema(schema string, ctx context.Context, dest any, sql string, args ...any) error
 	Procedure(ctx context.Context, sql string, args ...any) error
 	ProcedureWithSchema(schema string, ctx context.Context, sql string, arguments ...any) error
-	ExecuteTransactions(ctx context.Context, dataExec ...adapters.DataExec) error
-	ExecuteTransactionsWithSchema(schema string, ctx context.Context, dataExec ...adapters.DataExec) error
-	ExecuteTransactionsMulti(ctx context.Context, dataExec ...[]adapters.DataExec) error
-	ExecuteTransactionsMultiWithSchema(schema string, ctx context.Context, dataExec ...[]adapters.DataExec) error
+	ExecuteTransactions(ctx context.Context, dataExec ...adapters.DataExec) ([]adapters.ResultExec, error)
+	ExecuteTransactionsWithSchema(schema string, ctx context.Context, dataExec ...adapters.DataExec) ([]adapters.ResultExec, error)
+	ExecuteTransactionsMulti(ctx context.Context, dataExec ...[]adapters.DataExec) ([][]adapters.ResultExec, error)
+	ExecuteTransactionsMultiWithSchema(schema string, ctx context.Context, dataExec ...[]adapters.DataExec) ([][]adapters.ResultExec, error)
 	Pool() *pgxpool.Pool
 }
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+
+	"github.com/deybin/pgorm/internal/adapters"
+	"github.com/deybin/pgorm/internal/core/domain"
+	"github.com/deybin/pgorm/migrator"
+	"github.com/jackc/pgx/v5"
+)
+
+// hookTargets devuelve el esquema y las entidades de la transacción, en ese orden se ejecutan los hooks
+func hookTargets(ts *domain.Transactions) []any {
+	targets := []any{ts.Schema()}
+	for _, item := range ts.Datos() {
+		switch v := item.(type) {
+		case migrator.EntityUpdate:
+			targets = append(targets, v.Entity)
+		case *migrator.EntityUpdate:
+			targets = append(targets, v.Entity)
+		default:
+			targets = append(targets, item)
+		}
+	}
+	return targets
+}
+
+// hookAction agrupa las acciones según los hooks que ejecutan: Upsert ejecuta los hooks de Insert
+func hookAction(action adapters.Actions) adapters.Actions {
+	if action == adapters.UPSERT {
+		return adapters.INSERT
+	}
+	return action
+}
+
+func beforeHook(ctx context.Context, action adapters.Actions, target any) error {
+	switch action {
+	case adapters.INSERT:
+		if h, ok := target.(migrator.BeforeInsert); ok {
+			return h.BeforeInsert(ctx)
+		}
+	case adapters.UPDATE:
+		if h, ok := target.(migrator.BeforeUpdate); ok {
+			return h.BeforeUpdate(ctx)
+		}
+	case adapters.DELETE:
+		if h, ok := target.(migrator.BeforeDelete); ok {
+			return h.BeforeDelete(ctx)
+		}
+	}
+	return nil
+}
+
+func afterHook(ctx context.Context, action adapters.Actions, target any) error {
+	switch action {
+	case adapters.INSERT:
+		if h, ok := target.(migrator.AfterInsert); ok {
+			return h.AfterInsert(ctx)
+		}
+	case adapters.UPDATE:
+		if h, ok := target.(migrator.AfterUpdate); ok {
+			return h.AfterUpdate(ctx)
+		}
+	case adapters.DELETE:
+		if h, ok := target.(migrator.AfterDelete); ok {
+			return h.AfterDelete(ctx)
+		}
+	}
+	return nil
+}
+
+// afterTxHook devuelve el hook que se ejecuta dentro de la transacción o nil si target no lo implementa
+func afterTxHook(action adapters.Actions, target any) func(context.Context, pgx.Tx) error {
+	switch action {
+	case adapters.INSERT:
+		if h, ok := target.(migrator.AfterInsertTx); ok {
+			return h.AfterInsertTx
+		}
+	case adapters.UPDATE:
+		if h, ok := target.(migrator.AfterUpdateTx); ok {
+			return h.AfterUpdateTx
+		}
+	case adapters.DELETE:
+		if h, ok := target.(migrator.AfterDeleteTx); ok {
+			return h.AfterDeleteTx
+		}
+	}
+	return nil
+}
+
+// beforeHooks ejecuta los hooks Before del esquema y de las entidades con el contexto establecido por WithContext
+func (sq *SqlExecSingles) beforeHooks() error {
+	ctx := sq.Transactions.Context()
+	action := hookAction(sq.Transactions.Action())
+	for _, target := range hookTargets(sq.Transaction()) {
+		if err := beforeHook(ctx, action, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attachAfterTx asigna a la ultima sentencia generada los hooks que se ejecutan dentro de la transacción
+func (sq *SqlExecSingles) attachAfterTx() {
+	action := hookAction(sq.Transactions.Action())
+	var hooks []func(context.Context, pgx.Tx) error
+	for _, target := range hookTargets(sq.Transaction()) {
+		if hook := afterTxHook(action, target); hook != nil {
+			hooks = append(hooks, hook)
+		}
+	}
+	query := sq.Transactions.Query()
+	if len(hooks) == 0 || len(query) == 0 {
+		return
+	}
+	query[len(query)-1].AfterTx = func(ctx context.Context, tx pgx.Tx) error {
+		for _, hook := range hooks {
+			if err := hook(ctx, tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	sq.Transaction().SetQuery(query)
+}
+
+/*
+AfterHooks ejecuta los hooks After (AfterInsert, AfterUpdate, AfterDelete) del esquema y de las entidades.
+
+ExecTransaction lo llama después del commit, un error en los hooks no revierte los cambios.
+
+	Parámetros
+		* ctx {context.Context}: contexto de la ejecución
+	Return
+		- (error): primer error retornado por un hook
+*/
+func (sq *SqlExecSingles) AfterHooks(ctx context.Context) error {
+	action := hookAction(sq.Transactions.Action())
+	for _, target := range hookTargets(sq.Transaction()) {
+		if err := afterHook(ctx, action, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AfterHooks ejecuta los hooks After de cada transacción procesada, ver SqlExecSingles.AfterHooks
+func (sq *SqlExecMultiples) AfterHooks(ctx context.Context) error {
+	for _, v := range sq.GetTransactions() {
+		if err := v.AfterHooks(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
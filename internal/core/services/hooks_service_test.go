@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/deybin/pgorm/internal/core/domain"
+	"github.com/deybin/pgorm/migrator"
+	"github.com/jackc/pgx/v5"
+)
+
+type hookItem struct {
+	Id    string  `validate:"primaryKey;required"`
+	Total float64 `validate:"required"`
+}
+
+func (e *hookItem) BeforeInsert(ctx context.Context) error {
+	if e.Total < 0 {
+		return errors.New("el total no puede ser negativo")
+	}
+	e.Id = strings.ToUpper(strings.TrimSpace(e.Id))
+	return nil
+}
+
+func (e hookItem) Name() string      { return "hook_items" }
+func (e hookItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e hookItem) Values() []any     { return migrator.EntityValues(e) }
+
+type hookSchema struct{ table hookItem }
+
+func (s hookSchema) Table() migrator.Entity { return s.table }
+func (s hookSchema) ParseInsert() []migrator.Fields {
+	return migrator.GenerateSchema(s.table, migrator.INSERT)
+}
+func (s hookSchema) ParseUpdate() []migrator.Fields {
+	return migrator.GenerateSchema(s.table, migrator.UPDATE)
+}
+func (s hookSchema) ParseDelete() []migrator.Fields {
+	return migrator.GenerateSchema(s.table, migrator.DELETE)
+}
+func (s hookSchema) AfterInsertTx(ctx context.Context, tx pgx.Tx) error {
+	return nil
+}
+
+func TestHooks_Insert(t *testing.T) {
+	item := &hookItem{Id: " f-1 ", Total: 10}
+	crud := (&SqlExecSingles{Transactions: domain.NewTransaction(hookSchema{}, item)}).WithContext(context.Background())
+	if err := crud.Insert(); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	query := crud.Transactions.Query()[0]
+	if item.Id != "F-1" || query.Values[0] != "F-1" {
+		t.Errorf("BeforeInsert no normalizo la entidad: %v", query.Values)
+	}
+	if query.AfterTx == nil {
+		t.Errorf("se esperaba el hook AfterInsertTx en la sentencia")
+	}
+
+	invalid := &SqlExecSingles{Transactions: domain.NewTransaction(hookSchema{}, &hookItem{Id: "F-2", Total: -1})}
+	if err := invalid.Insert(); err == nil {
+		t.Errorf("se esperaba el error de BeforeInsert")
+	}
+}
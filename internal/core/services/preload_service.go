@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/deybin/pgorm/internal/core/domain"
+	"github.com/deybin/pgorm/internal/core/mapper"
+	"github.com/deybin/pgorm/internal/core/ports"
+	"github.com/deybin/pgorm/migrator"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// preloadOwner es la columna que identifica al dueño en las consultas de relaciones muchos a muchos
+const preloadOwner = "pgorm_owner"
+
+/*
+Preload carga las relaciones indicadas sobre el resultado de la consulta principal.
+
+Por cada relación se ejecuta una sola consulta `= ANY($1)` con las llaves de todos los registros de dest
+y los registros obtenidos se asignan al campo de cada dueño (slice para HasMany/ManyToMany, struct o puntero para HasOne/BelongsTo).
+Si la relación declara Model y su esquema usa softDelete, los registros relacionados eliminados lógicamente
+se excluyen salvo que scope sea domain.IncludeDeleted.
+
+	Parámetros
+		* db {ports.DBPort}: conexión a la base de datos
+		* ctx {context.Context}: contexto de la consulta (respeta el schema establecido con SchemaId)
+		* dest {any}: puntero al struct o slice de structs ya cargado por la consulta principal
+		* schema {migrator.Schema}: esquema que declara las relaciones
+		* scope {domain.DeletedScope}: registros eliminados lógicamente que incluye la consulta principal
+		* relations {...string}: nombres de las relaciones a cargar
+	Return
+		- (error) retorna errores ocurridos al cargar las relaciones
+*/
+func Preload(db ports.DBPort, ctx context.Context, dest any, schema migrator.Schema, scope domain.DeletedScope, relations ...string) error {
+	if len(relations) == 0 {
+		return nil
+	}
+	if schema == nil {
+		return errors.New("no se definió el modelo (Model) para precargar relaciones")
+	}
+
+	owners := preloadOwners(reflect.ValueOf(dest))
+	if len(owners) == 0 {
+		return nil
+	}
+
+	for _, name := range relations {
+		rel, err := migrator.FindRelation(schema, name)
+		if err != nil {
+			return err
+		}
+		if err := preloadRelation(db, ctx, owners, rel, scope); err != nil {
+			return fmt.Errorf("relación %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// preloadOwners devuelve los structs (direccionables) contenidos en dest
+func preloadOwners(v reflect.Value) []reflect.Value {
+	v = reflect.Indirect(v)
+	var owners []reflect.Value
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			item := reflect.Indirect(v.Index(i))
+			if item.Kind() == reflect.Struct {
+				owners = append(owners, item)
+			}
+		}
+	case reflect.Struct:
+		owners = append(owners, v)
+	}
+	return owners
+}
+
+func preloadRelation(db ports.DBPort, ctx context.Context, owners []reflect.Value, rel migrator.Relation, scope domain.DeletedScope) error {
+	ownerColumn, matchColumn, query := preloadQuery(rel, scope)
+
+	var keys []any
+	seen := map[string]bool{}
+	for _, owner := range owners {
+		if !owner.FieldByName(rel.Name).IsValid() {
+			return fmt.Errorf("el campo %s no existe en %s", rel.Name, owner.Type())
+		}
+		value, ok := mapper.FieldByColumn(owner, ownerColumn)
+		if !ok {
+			return fmt.Errorf("la columna %s no existe en %s", ownerColumn, owner.Type())
+		}
+		if value.IsZero() {
+			continue
+		}
+		key := preloadKey(reflect.Indirect(value).Interface())
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, reflect.Indirect(value).Interface())
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	rows, err := db.Execute(ctx, query, keys)
+	if err != nil {
+		return err
+	}
+
+	grouped := map[string][]map[string]any{}
+	for _, row := range rows {
+		key := preloadKey(row[matchColumn])
+		grouped[key] = append(grouped[key], row)
+	}
+
+	for _, owner := range owners {
+		value, _ := mapper.FieldByColumn(owner, ownerColumn)
+		if value.IsZero() {
+			continue
+		}
+		group := grouped[preloadKey(reflect.Indirect(value).Interface())]
+		if len(group) == 0 {
+			continue
+		}
+		if err := preloadAssign(owner.FieldByName(rel.Name), group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+preloadQuery genera la consulta de una relación.
+
+	Return
+		- (string) columna del dueño que contiene la llave
+		- (string) columna del resultado que se compara con la llave del dueño
+		- (string) consulta sql con el placeholder $1 para el arreglo de llaves
+*/
+func preloadQuery(rel migrator.Relation, scope domain.DeletedScope) (string, string, string) {
+	deleted := preloadDeleted(rel, scope)
+	switch rel.Type {
+	case migrator.BelongsTo:
+		return rel.ForeignKey, rel.References, fmt.Sprintf("SELECT * FROM %s WHERE %s.%s = ANY($1)%s", rel.Table, rel.Table, rel.References, deleted)
+	case migrator.ManyToMany:
+		return rel.References, preloadOwner, fmt.Sprintf("SELECT %s.*, %s.%s AS %s FROM %s INNER JOIN %s ON %s.%s = %s.%s WHERE %s.%s = ANY($1)%s",
+			rel.Table, rel.JoinTable, rel.JoinForeignKey, preloadOwner,
+			rel.Table, rel.JoinTable, rel.JoinTable, rel.JoinReferences, rel.Table, rel.ForeignKey,
+			rel.JoinTable, rel.JoinForeignKey, deleted)
+	default:
+		return rel.References, rel.ForeignKey, fmt.Sprintf("SELECT * FROM %s WHERE %s.%s = ANY($1)%s", rel.Table, rel.Table, rel.ForeignKey, deleted)
+	}
+}
+
+// preloadDeleted devuelve la condición que excluye los registros relacionados eliminados lógicamente, vacía si no aplica
+func preloadDeleted(rel migrator.Relation, scope domain.DeletedScope) string {
+	if rel.Model == nil || scope == domain.IncludeDeleted {
+		return ""
+	}
+	field, ok := migrator.SoftDeleteField(rel.Model.ParseDelete())
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf(" AND %s.%s IS NULL", rel.Table, field.Name)
+}
+
+/*
+preloadKey normaliza el valor de una llave para agrupar los registros relacionados con su dueño.
+
+Las llaves del dueño y de las filas pueden llegar con tipos distintos para el mismo valor
+(int64 y int32, uuid.UUID y string, pgtype.Numeric y float64), por eso se comparan por su representación normalizada.
+*/
+func preloadKey(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case pgtype.Numeric:
+		if !v.Valid {
+			return ""
+		}
+		if v.NaN || v.InfinityModifier != pgtype.Finite {
+			text, _ := v.Value()
+			return fmt.Sprint(text)
+		}
+		num := new(big.Rat).SetInt(v.Int)
+		exp := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(max(v.Exp, -v.Exp))), nil))
+		if v.Exp > 0 {
+			num.Mul(num, exp)
+		} else {
+			num.Quo(num, exp)
+		}
+		return num.RatString()
+	case [16]byte:
+		return uuid.UUID(v).String()
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case driver.Valuer:
+		if normalized, err := v.Value(); err == nil {
+			return preloadKey(normalized)
+		}
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		if num, ok := new(big.Rat).SetString(strconv.FormatFloat(rv.Float(), 'f', -1, 64)); ok {
+			return num.RatString()
+		}
+	case reflect.String:
+		return rv.String()
+	}
+	return fmt.Sprint(value)
+}
+
+// preloadAssign mapea las filas relacionadas al campo del dueño
+func preloadAssign(field reflect.Value, rows []map[string]any) error {
+	if field.Kind() == reflect.Slice {
+		target := reflect.New(field.Type())
+		if err := mapper.Nested(rows, target.Interface()); err != nil {
+			return err
+		}
+		field.Set(target.Elem())
+		return nil
+	}
+
+	target := reflect.New(field.Type())
+	if err := mapper.Nested(rows[:1], target.Interface()); err != nil {
+		return err
+	}
+	field.Set(target.Elem())
+	return nil
+}
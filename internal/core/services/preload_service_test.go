@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deybin/pgorm/internal/core/domain"
+	"github.com/deybin/pgorm/internal/core/ports"
+	"github.com/deybin/pgorm/migrator"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// preloadDB es un ports.DBPort falso que registra las consultas y responde con las filas de rows
+type preloadDB struct {
+	ports.DBPort
+	queries []string
+	args    [][]any
+	rows    func(sql string) []map[string]any
+}
+
+func (db *preloadDB) Execute(ctx context.Context, sql string, args ...any) ([]map[string]any, error) {
+	db.queries = append(db.queries, sql)
+	db.args = append(db.args, args)
+	return db.rows(sql), nil
+}
+
+type customerEntity struct {
+	Id int64 `validate:"primaryKey"`
+}
+
+func (e customerEntity) Name() string      { return "customers" }
+func (e customerEntity) Columns() []string { return migrator.EntityColumns(e) }
+func (e customerEntity) Values() []any     { return migrator.EntityValues(e) }
+
+type orderEntity struct {
+	Id          int64     `validate:"primaryKey"`
+	Customer_id int64     `validate:"required"`
+	Deleted_at  time.Time `validate:"softDelete"`
+}
+
+func (e orderEntity) Name() string      { return "orders" }
+func (e orderEntity) Columns() []string { return migrator.EntityColumns(e) }
+func (e orderEntity) Values() []any     { return migrator.EntityValues(e) }
+
+type customerSchema struct{ table customerEntity }
+
+func (s customerSchema) Table() migrator.Entity { return s.table }
+func (s customerSchema) ParseInsert() []migrator.Fields {
+	return migrator.GenerateSchema(s.table, migrator.INSERT)
+}
+func (s customerSchema) ParseUpdate() []migrator.Fields {
+	return migrator.GenerateSchema(s.table, migrator.UPDATE)
+}
+func (s customerSchema) ParseDelete() []migrator.Fields {
+	return migrator.GenerateSchema(s.table, migrator.DELETE)
+}
+func (s customerSchema) Relations() []migrator.Relation {
+	return []migrator.Relation{
+		{Name: "Orders", Type: migrator.HasMany, Model: orderSchema{}, ForeignKey: "customer_id"},
+		{Name: "Tags", Type: migrator.ManyToMany, Table: "tags", JoinTable: "customer_tags", JoinForeignKey: "customer_id", JoinReferences: "tag_id"},
+		{Name: "Broken", Type: migrator.HasOne, Table: "orders"},
+	}
+}
+
+type orderSchema struct{ table orderEntity }
+
+func (s orderSchema) Table() migrator.Entity { return s.table }
+func (s orderSchema) ParseInsert() []migrator.Fields {
+	return migrator.GenerateSchema(s.table, migrator.INSERT)
+}
+func (s orderSchema) ParseUpdate() []migrator.Fields {
+	return migrator.GenerateSchema(s.table, migrator.UPDATE)
+}
+func (s orderSchema) ParseDelete() []migrator.Fields {
+	return migrator.GenerateSchema(s.table, migrator.DELETE)
+}
+func (s orderSchema) Relations() []migrator.Relation {
+	return []migrator.Relation{
+		{Name: "Customer", Type: migrator.BelongsTo, Model: customerSchema{}, ForeignKey: "customer_id"},
+	}
+}
+
+type preloadCustomer struct {
+	Id     int64          `db:"id" validate:"primaryKey"`
+	Name   string         `db:"name"`
+	Orders []preloadOrder `db:"orders"`
+}
+
+type preloadOrder struct {
+	Id         int64            `db:"id" validate:"primaryKey"`
+	CustomerId int64            `db:"customer_id"`
+	Customer   *preloadCustomer `db:"customer"`
+}
+
+func TestFindRelation(t *testing.T) {
+	rel, err := migrator.FindRelation(customerSchema{}, "Orders")
+	if err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	if rel.Table != "orders" || rel.References != "id" || rel.ForeignKey != "customer_id" {
+		t.Errorf("relación inesperada: %+v", rel)
+	}
+
+	rel, err = migrator.FindRelation(customerSchema{}, "Tags")
+	if err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	if rel.References != "id" || rel.ForeignKey != "id" {
+		t.Errorf("ManyToMany debe usar id por defecto: %+v", rel)
+	}
+
+	for _, name := range []string{"Broken", "Missing"} {
+		if _, err := migrator.FindRelation(customerSchema{}, name); err == nil {
+			t.Errorf("%s: se esperaba un error", name)
+		}
+	}
+}
+
+func TestPreloadQuery(t *testing.T) {
+	tests := []struct {
+		relation string
+		schema   migrator.Schema
+		scope    domain.DeletedScope
+		owner    string
+		match    string
+		sql      string
+	}{
+		{"Orders", customerSchema{}, domain.ExcludeDeleted, "id", "customer_id",
+			"SELECT * FROM orders WHERE orders.customer_id = ANY($1) AND orders.deleted_at IS NULL"},
+		{"Orders", customerSchema{}, domain.IncludeDeleted, "id", "customer_id",
+			"SELECT * FROM orders WHERE orders.customer_id = ANY($1)"},
+		{"Customer", orderSchema{}, domain.ExcludeDeleted, "customer_id", "id",
+			"SELECT * FROM customers WHERE customers.id = ANY($1)"},
+		{"Tags", customerSchema{}, domain.ExcludeDeleted, "id", preloadOwner,
+			"SELECT tags.*, customer_tags.customer_id AS pgorm_owner FROM tags INNER JOIN customer_tags ON customer_tags.tag_id = tags.id WHERE customer_tags.customer_id = ANY($1)"},
+	}
+	for _, tt := range tests {
+		rel, err := migrator.FindRelation(tt.schema, tt.relation)
+		if err != nil {
+			t.Fatalf("%s: no se esperaba este error: %v", tt.relation, err)
+		}
+		owner, match, sql := preloadQuery(rel, tt.scope)
+		if owner != tt.owner || match != tt.match || sql != tt.sql {
+			t.Errorf("%s:\n got  %s, %s, %s\n want %s, %s, %s", tt.relation, owner, match, sql, tt.owner, tt.match, tt.sql)
+		}
+	}
+}
+
+func TestPreloadKey(t *testing.T) {
+	id := uuid.New()
+	tests := []struct {
+		a, b any
+	}{
+		{int64(5), int32(5)},
+		{uint8(5), pgtype.Numeric{Int: big.NewInt(500), Exp: -2, Valid: true}},
+		{float64(2.5), pgtype.Numeric{Int: big.NewInt(25), Exp: -1, Valid: true}},
+		{id, id.String()},
+		{[16]byte(id), pgtype.UUID{Bytes: id, Valid: true}},
+		{pgtype.Int8{Int64: 7, Valid: true}, 7},
+	}
+	for _, tt := range tests {
+		if preloadKey(tt.a) != preloadKey(tt.b) {
+			t.Errorf("%T %v y %T %v deben tener la misma llave: %q != %q", tt.a, tt.a, tt.b, tt.b, preloadKey(tt.a), preloadKey(tt.b))
+		}
+	}
+	if preloadKey(int64(5)) == preloadKey(int64(50)) {
+		t.Error("llaves distintas no deben coincidir")
+	}
+}
+
+func TestPreload_GroupsAndAssigns(t *testing.T) {
+	db := &preloadDB{rows: func(string) []map[string]any {
+		return []map[string]any{
+			{"id": int64(10), "customer_id": int32(1)},
+			{"id": int64(11), "customer_id": int32(1)},
+			{"id": int64(20), "customer_id": int32(2)},
+		}
+	}}
+	customers := []preloadCustomer{{Id: 1, Name: "ana"}, {Id: 2, Name: "luis"}, {Id: 1, Name: "ana"}, {Id: 3, Name: "eva"}}
+	if err := Preload(db, context.Background(), &customers, customerSchema{}, domain.ExcludeDeleted, "Orders"); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+
+	if len(db.queries) != 1 || !strings.HasSuffix(db.queries[0], "deleted_at IS NULL") {
+		t.Fatalf("se esperaba una consulta con el filtro de eliminados: %v", db.queries)
+	}
+	if keys, _ := db.args[0][0].([]any); len(keys) != 3 {
+		t.Errorf("las llaves deben enviarse sin repetir: %v", db.args[0])
+	}
+	if len(customers[0].Orders) != 2 || len(customers[1].Orders) != 1 || len(customers[2].Orders) != 2 || customers[3].Orders != nil {
+		t.Errorf("relaciones asignadas incorrectamente: %+v", customers)
+	}
+	if customers[1].Orders[0].Id != 20 || customers[1].Orders[0].Customer != nil {
+		t.Errorf("registro relacionado inesperado: %+v", customers[1].Orders[0])
+	}
+}
+
+func TestPreload_TwoWayRelation(t *testing.T) {
+	db := &preloadDB{rows: func(sql string) []map[string]any {
+		return []map[string]any{{"id": int64(1), "name": "ana"}}
+	}}
+	orders := []preloadOrder{{Id: 10, CustomerId: 1}, {Id: 11, CustomerId: 1}, {Id: 12}}
+	if err := Preload(db, context.Background(), &orders, orderSchema{}, domain.ExcludeDeleted, "Customer"); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	if orders[0].Customer == nil || orders[0].Customer.Name != "ana" || orders[1].Customer == nil || orders[2].Customer != nil {
+		t.Errorf("relaciones asignadas incorrectamente: %+v", orders)
+	}
+
+	var order preloadOrder
+	if err := Preload(db, context.Background(), &order, orderSchema{}, domain.ExcludeDeleted, "Missing"); err == nil {
+		t.Error("se esperaba un error por relación inexistente")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/deybin/pgorm/internal/core/builder"
 	"github.com/deybin/pgorm/internal/core/clause"
 	"github.com/deybin/pgorm/internal/core/domain"
+	"github.com/deybin/pgorm/migrator"
 )
 
 type Query struct {
@@ -66,6 +67,31 @@ func (q *Query) Join(tp clause.TypeJoin, table string, on string) *Query {
 	return q
 }
 
+func (q *Query) Model(s migrator.Schema) *Query {
+	q.Sintaxis.Model(s)
+	return q
+}
+
+func (q *Query) Preload(relations ...string) *Query {
+	q.Sintaxis.Preload(relations...)
+	return q
+}
+
+func (q *Query) WithDeleted() *Query {
+	q.Sintaxis.WithDeleted()
+	return q
+}
+
+func (q *Query) OnlyDeleted() *Query {
+	q.Sintaxis.OnlyDeleted()
+	return q
+}
+
+func (q *Query) Nested() *Query {
+	q.Sintaxis.Nested()
+	return q
+}
+
 func (q Query) String() string {
 	return builder.BuildQuery(q.Sintaxis)
 }
@@ -1,13 +1,14 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/deybin/pgorm/internal/adapters"
 	"github.com/deybin/pgorm/internal/core/builder"
 	"github.com/deybin/pgorm/internal/core/domain"
+	"github.com/deybin/pgorm/internal/core/mapper"
 	"github.com/deybin/pgorm/migrator"
 )
 
@@ -46,6 +47,76 @@ func (sq *SqlExecSingles) Datos() []migrator.Entity {
 	return sq.Transactions.Datos()
 }
 
+/*
+Returning solicita que las sentencias generadas devuelvan las columnas indicadas (todas si no se indican).
+
+Debe llamarse antes de Insert, BulkInsert, Upsert, Update o Delete. Al ejecutar la transacción los registros
+devueltos reemplazan los datos de la transacción (Transactions.Data) y pueden leerse con Scan.
+Las entidades recibidas no se modifican, para obtener los valores generados por la base de datos se usa Scan.
+
+	Parámetros
+		* columns {...string}: nombres de las columnas a devolver (se escriben entre comillas como identificadores), sin columnas se devuelven todas (*)
+	Return
+		- (*SqlExecSingles) retorna el mismo puntero para encadenar la operación
+*/
+func (sq *SqlExecSingles) Returning(columns ...string) *SqlExecSingles {
+	sq.Transaction().SetReturning(columns...)
+	return sq
+}
+
+/*
+WithContext establece el contexto que reciben los hooks Before (BeforeInsert, BeforeUpdate, BeforeDelete),
+también selecciona el idioma de los mensajes de validación (logger.WithLanguage)
+
+	Parámetros
+		* ctx {context.Context}: contexto de la operación
+*/
+func (sq *SqlExecSingles) WithContext(ctx context.Context) *SqlExecSingles {
+	sq.Transaction().SetContext(ctx)
+	return sq
+}
+
+/*
+ExpectRows hace que cada sentencia de Update/Delete deba afectar al menos un registro y como máximo maxRows,
+si no se cumple ExecTransaction retorna *adapters.ErrRowsAffected y revierte la transacción.
+
+	Parámetros
+		* maxRows {int64}: máximo de registros que puede afectar cada sentencia, 0 sin limite
+*/
+func (sq *SqlExecSingles) ExpectRows(maxRows int64) *SqlExecSingles {
+	sq.Transaction().SetExpect(maxRows)
+	return sq
+}
+
+/*
+SetResults guarda en la transacción los registros devueltos por RETURNING al ejecutarla.
+
+	Parámetros
+		* results {[]adapters.ResultExec}: resultados en el mismo orden que Transactions.Query()
+*/
+func (sq *SqlExecSingles) SetResults(results []adapters.ResultExec) {
+	if sq.Transactions.Returning() == "" {
+		return
+	}
+	data := make([]map[string]any, 0, len(results))
+	for _, result := range results {
+		data = append(data, result.Rows...)
+	}
+	sq.Transaction().SetData(data)
+}
+
+/*
+Scan copia los datos de la transacción (los registros devueltos por RETURNING si se solicito) en dest.
+
+	Parámetros
+		* dest {any}: puntero a un struct o slice de structs, las columnas se relacionan por la etiqueta `db` o el nombre del campo
+	Return
+		- (error): retorna errores ocurridos al asignar los valores
+*/
+func (sq *SqlExecSingles) Scan(dest any) error {
+	return mapper.Nested(sq.Transactions.Data(), dest)
+}
+
 /*
 Valida los datos para insertar y crea el query para insertar
 
@@ -54,9 +125,57 @@ Valida los datos para insertar y crea el query para insertar
 */
 func (sq *SqlExecSingles) Insert() error {
 	sq.Transaction().SetAction(adapters.INSERT)
+	if err := sq.beforeHooks(); err != nil {
+		return err
+	}
 	if err := builder.BuilderInsertGeneric(sq.Transaction()); err != nil {
 		return err
 	}
+	sq.attachAfterTx()
+	return nil
+}
+
+/*
+Valida los datos para insertar y prepara su envío masivo mediante COPY FROM.
+
+Cada registro pasa por las mismas validaciones que Insert, los registros que comparten las mismas columnas
+se envían en un solo COPY FROM en lugar de una sentencia INSERT por registro.
+
+	Return
+		- (error): retorna errores ocurridos en la validación
+*/
+func (sq *SqlExecSingles) BulkInsert() error {
+	sq.Transaction().SetAction(adapters.INSERT)
+	if err := sq.beforeHooks(); err != nil {
+		return err
+	}
+	if err := builder.BuilderBulkInsertGeneric(sq.Transaction()); err != nil {
+		return err
+	}
+	sq.attachAfterTx()
+	return nil
+}
+
+/*
+Valida los datos con las reglas de inserción y crea el query INSERT ... ON CONFLICT
+
+Cuando el registro ya existe se actualizan solo los campos marcados con `update`,
+si no existen campos para actualizar o se indica DoNothing el registro en conflicto se ignora.
+
+	Parámetros
+		* opts {migrator.UpsertOptions}: restricción o columnas del conflicto, por defecto la llave primaria
+	Return
+		- (error): retorna errores ocurridos en la validación
+*/
+func (sq *SqlExecSingles) Upsert(opts migrator.UpsertOptions) error {
+	sq.Transaction().SetAction(adapters.UPSERT)
+	if err := sq.beforeHooks(); err != nil {
+		return err
+	}
+	if err := builder.BuilderUpsertGeneric(sq.Transaction(), opts); err != nil {
+		return err
+	}
+	sq.attachAfterTx()
 	return nil
 }
 
@@ -68,23 +187,109 @@ Valida los datos para actualizar y crea el query para actualizar
 */
 func (sq *SqlExecSingles) Update() error {
 	sq.Transaction().SetAction(adapters.UPDATE)
+	if err := sq.beforeHooks(); err != nil {
+		return err
+	}
 	if err := builder.BuilderUpdateGeneric(sq.Transaction()); err != nil {
 		return err
 	}
+	sq.attachAfterTx()
 	return nil
 }
 
 /*
-Valida los datos para Eliminar y crea el query para Eliminar
+Valida cada EntityUpdate igual que Update y crea un solo UPDATE ... FROM (VALUES ...) por grupo de registros que actualizan las mismas columnas.
+
+Las condiciones de cada EntityUpdate deben ser `=` sobre la llave primaria, las sentencias se dividen para no superar el limite de parámetros.
+
+	Return
+		- (error): retorna errores ocurridos en la validación
+*/
+func (sq *SqlExecSingles) BulkUpdate() error {
+	sq.Transaction().SetAction(adapters.UPDATE)
+	if err := sq.beforeHooks(); err != nil {
+		return err
+	}
+	if err := builder.BuilderBulkUpdateGeneric(sq.Transaction()); err != nil {
+		return err
+	}
+	sq.attachAfterTx()
+	return nil
+}
+
+/*
+Valida los datos para Eliminar y crea el query para Eliminar.
+
+Si el esquema tiene un campo `validate:"softDelete"` no se elimina el registro, se establece la fecha de eliminación en ese campo.
 
 	Return
 		- (error): retorna errores ocurridos en la validación
 */
 func (sq *SqlExecSingles) Delete(dataDelete ...migrator.Where) error {
 	sq.Transaction().SetAction(adapters.DELETE)
+	if err := sq.beforeHooks(); err != nil {
+		return err
+	}
 	if err := builder.BuilderDeleteGeneric(sq.Transaction(), dataDelete); err != nil {
-		return errors.New(strings.Join(sq.errors, "; "))
+		return err
+	}
+	sq.attachAfterTx()
+	return nil
+}
+
+/*
+BulkDelete elimina en una sola sentencia todas las entidades de la transacción por su llave primaria.
+
+Las llaves se validan con el esquema ParseDelete, si el esquema usa eliminación lógica (softDelete) se establece la fecha de eliminación.
+
+	Return
+		- (error): retorna errores ocurridos en la validación
+*/
+func (sq *SqlExecSingles) BulkDelete() error {
+	sq.Transaction().SetAction(adapters.DELETE)
+	if err := sq.beforeHooks(); err != nil {
+		return err
+	}
+	if err := builder.BuilderBulkDeleteGeneric(sq.Transaction()); err != nil {
+		return err
 	}
+	sq.attachAfterTx()
+	return nil
+}
+
+/*
+HardDelete elimina físicamente los registros aunque el esquema use eliminación lógica (softDelete)
+
+	Return
+		- (error): retorna errores ocurridos en la validación
+*/
+func (sq *SqlExecSingles) HardDelete(dataDelete ...migrator.Where) error {
+	sq.Transaction().SetAction(adapters.DELETE)
+	if err := sq.beforeHooks(); err != nil {
+		return err
+	}
+	if err := builder.BuilderHardDeleteGeneric(sq.Transaction(), dataDelete); err != nil {
+		return err
+	}
+	sq.attachAfterTx()
+	return nil
+}
+
+/*
+Restore revierte la eliminación lógica de los registros que cumplen las condiciones
+
+	Return
+		- (error): retorna errores ocurridos en la validación o si el esquema no usa eliminación lógica
+*/
+func (sq *SqlExecSingles) Restore(dataRestore ...migrator.Where) error {
+	sq.Transaction().SetAction(adapters.UPDATE)
+	if err := sq.beforeHooks(); err != nil {
+		return err
+	}
+	if err := builder.BuilderRestoreGeneric(sq.Transaction(), dataRestore); err != nil {
+		return err
+	}
+	sq.attachAfterTx()
 	return nil
 }
 
@@ -144,6 +349,20 @@ func (sq *SqlExecMultiples) GetTransactions() []*SqlExecSingles {
 	return returned
 }
 
+/*
+SetResults reparte los resultados de la ejecución entre las transacciones procesadas.
+
+	Parámetros
+		* results {[][]adapters.ResultExec}: resultados en el mismo orden que DataExec()
+*/
+func (sq *SqlExecMultiples) SetResults(results [][]adapters.ResultExec) {
+	for i, v := range sq.GetTransactions() {
+		if i < len(results) {
+			v.SetResults(results[i])
+		}
+	}
+}
+
 /*
 GetTransaction retorna las transacciones ya procesadas.
 
@@ -1,61 +1,94 @@
 package logger
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"log/slog"
 	"strings"
 
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// ManagerErrors traduce los errores de PostgreSQL a mensajes del catalogo, Lang vació usa el idioma por defecto
 type ManagerErrors struct {
+	Lang Lang
+}
+
+// ManagerErrorsContext retorna un ManagerErrors con el idioma del contexto
+func ManagerErrorsContext(ctx context.Context) ManagerErrors {
+	return ManagerErrors{Lang: LanguageContext(ctx)}
+}
+
+// dbError es el mensaje traducido de un error de base de datos, conserva el error original para errors.Is y errors.As
+type dbError struct {
+	msg string
+	err error
+}
+
+func (e *dbError) Error() string { return e.msg }
+
+func (e *dbError) Unwrap() error { return e.err }
+
+// translate retorna el mensaje del código en el idioma del ManagerErrors envolviendo el error original
+func (c ManagerErrors) translate(err error, code string, args ...any) error {
+	return &dbError{msg: Errorf(c.lang(), code, args...).Error(), err: err}
+}
+
+func (c ManagerErrors) lang() Lang {
+	if c.Lang == "" {
+		return DefaultLanguage()
+	}
+	return c.Lang
 }
 
 func (c ManagerErrors) SqlConnections(err error) error {
-	if pgErr, ok := err.(*pgconn.ConnectError); ok {
+	var pgErr *pgconn.ConnectError
+	if errors.As(err, &pgErr) {
 		if strings.Contains(pgErr.Error(), "3D000") {
 			// Código de error 3D000 = "invalid_catalog_name" → base de datos no existe
-			return fmt.Errorf("base de datos no existe")
+			return c.translate(err, "db.database_not_found")
 		}
-		return fmt.Errorf("error PostgreSQL: %s", pgErr.Error())
+		return c.translate(err, "db.postgres", pgErr.Error())
 	}
 	// Otro tipo de error
-	return fmt.Errorf("error de conexión: %w", err)
+	return c.translate(err, "db.connection", err)
 
 }
 func (c ManagerErrors) SqlQuery(err error) error {
-	if pgErr, ok := err.(*pgconn.PgError); ok {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
 		switch pgErr.Code {
 		case "42P01":
-			return fmt.Errorf("tabla no existe")
+			return c.translate(err, "db.table_not_found")
 		case "23505":
-			return fmt.Errorf("duplicidad de registro")
+			return c.translate(err, "db.duplicate")
 		default:
-			return fmt.Errorf("error PostgreSQL: %s", pgErr.Error())
+			return c.translate(err, "db.postgres", pgErr.Error())
 		}
 
 	}
 	// Otro tipo de error
-	return fmt.Errorf("error de conexión: %w", err)
+	return c.translate(err, "db.connection", err)
 
 }
 
 func (c ManagerErrors) SqlCrud(err error, table string) error {
-	slog.Error("Fallo en la operación ("+table+") ", "error", err)
-	if pgErr, ok := err.(*pgconn.PgError); ok {
+	slog.Error(Message(c.lang(), "db.operation_failed", table), "error", err)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
 		switch pgErr.Code {
 		case "42P01":
-			return fmt.Errorf("tabla no existe")
+			return c.translate(err, "db.table_not_found")
 		case "23505":
-			return fmt.Errorf("duplicidad de registro")
+			return c.translate(err, "db.duplicate")
 		default:
 
-			return fmt.Errorf("error PostgreSQL: %s", pgErr.Error())
+			return c.translate(err, "db.postgres", pgErr.Error())
 		}
 
 	}
 	// Otro tipo de error
 
-	return fmt.Errorf("error de conexión: %w", err)
+	return c.translate(err, "db.connection", err)
 
 }
@@ -0,0 +1,29 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/deybin/pgorm/logger"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestManagerErrors_Language(t *testing.T) {
+	if err := (logger.ManagerErrors{Lang: logger.EN}).SqlQuery(&pgconn.PgError{Code: "23505"}); err.Error() != "duplicate record" {
+		t.Errorf("mensaje inesperado: %v", err)
+	}
+}
+
+func TestManagerErrors_KeepsOriginal(t *testing.T) {
+	ctx := logger.WithLanguage(context.Background(), logger.EN)
+	err := logger.ManagerErrorsContext(ctx).SqlQuery(&pgconn.PgError{Code: "42P01"})
+	var pgErr *pgconn.PgError
+	if err.Error() != "table does not exist" || !errors.As(err, &pgErr) || pgErr.Code != "42P01" {
+		t.Errorf("el error traducido debe conservar el original: %v", err)
+	}
+
+	if err := logger.ManagerErrorsContext(ctx).SqlQuery(context.Canceled); !errors.Is(err, context.Canceled) {
+		t.Errorf("se esperaba envolver context.Canceled: %v", err)
+	}
+}
@@ -0,0 +1,225 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+)
+
+// Lang es el idioma de los mensajes de validación y de base de datos
+type Lang string
+
+const (
+	ES Lang = "es"
+	EN Lang = "en"
+)
+
+type langKey struct{}
+
+var (
+	catalogMu   sync.RWMutex
+	defaultLang = ES
+	catalog     = map[Lang]map[string]string{
+		ES: {
+			"validate.required":         "El campo %s: (es Requerido)",
+			"validate.null":             "El campo %s no puede actualizarse a NULL",
+			"validate.empty":            "El campo %s no puede estar vació",
+			"validate.update":           "El campo %s no puede ser modificado",
+			"validate.type":             "Se encontró fallas al validar el campo %s: (tipo de dato no asignado)",
+			"validate.expr":             "Se encontró fallas al validar el campo %s: (no cumple con las características)",
+			"validate.encrypt":          "Se encontró fallas al validar el campo %s: (%s)",
+			"validate.cipher":           "Se encontró fallas al validar el campo %s: (%s)",
+			"validate.min.string":       "Se encontró fallas al validar el campo %s: (no Cumple los caracteres mínimos que debe tener (%v))",
+			"validate.max.string":       "Se encontró fallas al validar el campo %s: (no Cumple los caracteres máximos que debe tener (%v))",
+			"validate.menor":            "Se encontró fallas al validar el campo %s: (No puede se menor a %f)",
+			"validate.mayor":            "Se encontró fallas al validar el campo %s: (No puede se mayor a %f)",
+			"validate.negative":         "Se encontró fallas al validar el campo %s: (No puede ser negativo)",
+			"validate.min":              "Se encontró fallas al validar el campo %s: (No puede se menor a %v)",
+			"validate.max":              "Se encontró fallas al validar el campo %s: (No puede se mayor a %v)",
+			"validate.max.uint":         "Se encontró fallas al validar el campo %s: (no esta en el rango permitido)",
+			"validate.min.date":         "Se encontró fallas al validar el campo %s: (la fecha no puede ser anterior a %v)",
+			"validate.max.date":         "Se encontró fallas al validar el campo %s: (la fecha no puede ser posterior a %v)",
+			"validate.min.bytes":        "Se encontró fallas al validar el campo %s: (debe tener como mínimo %v bytes)",
+			"validate.max.bytes":        "Se encontró fallas al validar el campo %s: (debe tener como máximo %v bytes)",
+			"validate.version.uuid":     "Se encontró fallas al validar el campo %s: (el uuid debe ser de la versión %v)",
+			"validate.custom":           "Se encontró fallas al validar el campo %s: (%s)",
+			"validate.custom.unknown":   "Se encontró fallas al validar el campo %s: (la regla %s no esta registrada)",
+			"validate.required_if":      "El campo %s: (es Requerido cuando %s es %v)",
+			"validate.required_unless":  "El campo %s: (es Requerido salvo que %s sea %v)",
+			"validate.required_with":    "El campo %s: (es Requerido cuando se envía %s)",
+			"validate.required_without": "El campo %s: (es Requerido cuando no se envía %s)",
+			"validate.eqfield":          "Se encontró fallas al validar el campo %s: (debe ser igual a %s)",
+			"validate.nefield":          "Se encontró fallas al validar el campo %s: (debe ser diferente de %s)",
+			"validate.gtfield":          "Se encontró fallas al validar el campo %s: (debe ser mayor que %s)",
+			"validate.gtefield":         "Se encontró fallas al validar el campo %s: (debe ser mayor o igual que %s)",
+			"validate.ltfield":          "Se encontró fallas al validar el campo %s: (debe ser menor que %s)",
+			"validate.ltefield":         "Se encontró fallas al validar el campo %s: (debe ser menor o igual que %s)",
+			"validate.field.unknown":    "Se encontró fallas al validar el campo %s: (el campo %s no existe)",
+			"validate.divide":           "Se encontró fallas al validar el campo %s: (no puede dividirse entre cero)",
+			"validate.problem":          "Los datos enviados no son válidos",
+			"data.insert":               "no existen datos para insertar",
+			"data.update":               "no existen datos para actualizar",
+			"data.delete":               "no existen datos para eliminar",
+			"data.update.filtered":      "al realizar validaciones se filtro datos y se quedo sin información para actualizar",
+			"data.insert.columns":       "el registro %d no tiene columnas para insertar",
+			"where.field":               "El campo %s no puede ser utilizado de esta forma",
+			"where.condition":           "La condición %s del campo %s no es valida",
+			"where.clause":              "La cláusula %s del campo %s no es valida",
+			"where.list":                "El campo %s necesita una lista de valores para la condición %s",
+			"where.between":             "El campo %s necesita dos valores para la condición %s",
+			"where.no_value":            "El campo %s no necesita valor para la condición %s",
+			"where.no_list":             "El campo %s no acepta una lista de valores para la condición %s",
+			"where.unknown_field":       "Uno o más campos enviados no son válidos.",
+			"where.primary_key":         "Existen campos obligatorios sin information",
+			"db.database_not_found":     "base de datos no existe",
+			"db.table_not_found":        "tabla no existe",
+			"db.duplicate":              "duplicidad de registro",
+			"db.postgres":               "error PostgreSQL: %s",
+			"db.connection":             "error de conexión: %w",
+			"db.operation_failed":       "Fallo en la operación (%s) ",
+		},
+		EN: {
+			"validate.required":         "The field %s is required",
+			"validate.null":             "The field %s cannot be set to NULL",
+			"validate.empty":            "The field %s cannot be empty",
+			"validate.update":           "The field %s cannot be modified",
+			"validate.type":             "The field %s failed validation: (unsupported data type)",
+			"validate.expr":             "The field %s failed validation: (does not match the expected format)",
+			"validate.encrypt":          "The field %s failed validation: (%s)",
+			"validate.cipher":           "The field %s failed validation: (%s)",
+			"validate.min.string":       "The field %s failed validation: (must have at least %v characters)",
+			"validate.max.string":       "The field %s failed validation: (must have at most %v characters)",
+			"validate.menor":            "The field %s failed validation: (must be greater than %f)",
+			"validate.mayor":            "The field %s failed validation: (must be less than %f)",
+			"validate.negative":         "The field %s failed validation: (cannot be negative)",
+			"validate.min":              "The field %s failed validation: (cannot be less than %v)",
+			"validate.max":              "The field %s failed validation: (cannot be greater than %v)",
+			"validate.max.uint":         "The field %s failed validation: (out of the allowed range)",
+			"validate.min.date":         "The field %s failed validation: (the date cannot be before %v)",
+			"validate.max.date":         "The field %s failed validation: (the date cannot be after %v)",
+			"validate.min.bytes":        "The field %s failed validation: (must have at least %v bytes)",
+			"validate.max.bytes":        "The field %s failed validation: (must have at most %v bytes)",
+			"validate.version.uuid":     "The field %s failed validation: (the uuid must be version %v)",
+			"validate.custom":           "The field %s failed validation: (%s)",
+			"validate.custom.unknown":   "The field %s failed validation: (the rule %s is not registered)",
+			"validate.required_if":      "The field %s is required when %s is %v",
+			"validate.required_unless":  "The field %s is required unless %s is %v",
+			"validate.required_with":    "The field %s is required when %s is present",
+			"validate.required_without": "The field %s is required when %s is not present",
+			"validate.eqfield":          "The field %s failed validation: (must be equal to %s)",
+			"validate.nefield":          "The field %s failed validation: (must be different from %s)",
+			"validate.gtfield":          "The field %s failed validation: (must be greater than %s)",
+			"validate.gtefield":         "The field %s failed validation: (must be greater than or equal to %s)",
+			"validate.ltfield":          "The field %s failed validation: (must be less than %s)",
+			"validate.ltefield":         "The field %s failed validation: (must be less than or equal to %s)",
+			"validate.field.unknown":    "The field %s failed validation: (the field %s does not exist)",
+			"validate.divide":           "The field %s failed validation: (cannot be divided by zero)",
+			"validate.problem":          "The submitted data is not valid",
+			"data.insert":               "there is no data to insert",
+			"data.update":               "there is no data to update",
+			"data.delete":               "there is no data to delete",
+			"data.update.filtered":      "no data left to update after validation",
+			"data.insert.columns":       "the record %d has no columns to insert",
+			"where.field":               "The field %s cannot be used this way",
+			"where.condition":           "The condition %s of the field %s is not valid",
+			"where.clause":              "The clause %s of the field %s is not valid",
+			"where.list":                "The field %s needs a list of values for the condition %s",
+			"where.between":             "The field %s needs two values for the condition %s",
+			"where.no_value":            "The field %s does not take a value for the condition %s",
+			"where.no_list":             "The field %s does not accept a list of values for the condition %s",
+			"where.unknown_field":       "One or more submitted fields are not valid.",
+			"where.primary_key":         "Required fields are missing",
+			"db.database_not_found":     "database does not exist",
+			"db.table_not_found":        "table does not exist",
+			"db.duplicate":              "duplicate record",
+			"db.postgres":               "PostgreSQL error: %s",
+			"db.connection":             "connection error: %w",
+			"db.operation_failed":       "Operation failed (%s) ",
+		},
+	}
+)
+
+/*
+SetLanguage establece el idioma por defecto de los mensajes.
+
+	Parámetros
+		* lang {Lang}: idioma, debe existir en el catalogo (ES, EN o uno registrado con Register)
+*/
+func SetLanguage(lang Lang) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	defaultLang = lang
+}
+
+// DefaultLanguage retorna el idioma por defecto de los mensajes
+func DefaultLanguage() Lang {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	return defaultLang
+}
+
+// WithLanguage retorna un contexto que selecciona el idioma de los mensajes de las operaciones que lo reciban
+func WithLanguage(ctx context.Context, lang Lang) context.Context {
+	return context.WithValue(ctx, langKey{}, lang)
+}
+
+// LanguageContext retorna el idioma del contexto o el idioma por defecto si no tiene uno
+func LanguageContext(ctx context.Context) Lang {
+	if ctx != nil {
+		if lang, ok := ctx.Value(langKey{}).(Lang); ok && lang != "" {
+			return lang
+		}
+	}
+	return DefaultLanguage()
+}
+
+/*
+Register agrega o reemplaza mensajes del catalogo, permite traducir los mensajes a otro idioma o personalizar los existentes.
+
+	Parámetros
+		* lang {Lang}: idioma de los mensajes
+		* messages {map[string]string}: mensajes por código (por ejemplo `validate.required`), con los mismos verbos de formato que el mensaje original
+*/
+func Register(lang Lang, messages map[string]string) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	if catalog[lang] == nil {
+		catalog[lang] = make(map[string]string, len(messages))
+	}
+	maps.Copy(catalog[lang], messages)
+}
+
+/*
+Message retorna el mensaje del código en el idioma indicado, si no existe se busca en el idioma por defecto y luego en español.
+
+	Parámetros
+		* lang {Lang}: idioma del mensaje
+		* code {string}: código del mensaje
+		* args {...any}: argumentos del formato del mensaje
+	Return
+		- (string) mensaje con formato, el código si no existe en el catalogo
+*/
+func Message(lang Lang, code string, args ...any) string {
+	format := messageFormat(lang, code)
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Errorf es Message que retorna un error, acepta %w igual que fmt.Errorf
+func Errorf(lang Lang, code string, args ...any) error {
+	return fmt.Errorf(messageFormat(lang, code), args...)
+}
+
+func messageFormat(lang Lang, code string) string {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	for _, l := range []Lang{lang, defaultLang, ES} {
+		if format, ok := catalog[l][code]; ok {
+			return format
+		}
+	}
+	return code
+}
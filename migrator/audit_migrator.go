@@ -0,0 +1,20 @@
+package migrator
+
+/*
+Auditable es implementada opcionalmente por un Schema cuyas escrituras se registran en la tabla de auditoría.
+
+Por cada registro insertado, actualizado o eliminado se guarda, en la misma transacción, la tabla, la llave primaria,
+la acción, los campos modificados (valores anteriores y nuevos) y el usuario establecido en el contexto con ActorId.
+La tabla de auditoría (ConfigPgxAdapter.AuditTable, por defecto audit_log) debe tener las columnas:
+
+	table_name text, record_key text, action text, changes jsonb, actor text, created_at timestamptz
+*/
+type Auditable interface {
+	Audit() bool
+}
+
+// IsAudited indica si el esquema registra auditoría
+func IsAudited(s Schema) bool {
+	a, ok := s.(Auditable)
+	return ok && a.Audit()
+}
@@ -1,6 +1,7 @@
 package migrator
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
@@ -10,83 +11,165 @@ import (
 
 	"github.com/deybin/pgorm/internal/configs"
 	"github.com/deybin/pgorm/internal/utils"
+	"github.com/deybin/pgorm/logger"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// CheckInsertGeneric valida los campos de la entidad que se insertaran, los mensajes usan el idioma por defecto
 func CheckInsertGeneric(schema []Fields, tabla_map Entity) (map[string]any, error) {
-	var errs []string
+	return CheckInsertGenericContext(context.Background(), schema, tabla_map)
+}
+
+/*
+CheckInsertGenericContext valida los campos de la entidad que se insertaran.
+
+	Parámetros
+		* ctx {context.Context}: contexto de la operación, selecciona el idioma de los mensajes (logger.WithLanguage)
+		* schema {[]Fields}: campos generados con la acción INSERT
+		* tabla_map {Entity}: entidad a insertar
+	Return
+		- (map[string]any) columnas y valores a insertar
+		- (error) fallas de validación (ValidationErrors)
+*/
+func CheckInsertGenericContext(ctx context.Context, schema []Fields, tabla_map Entity) (map[string]any, error) {
+	return checkInsert(ctx, schema, tabla_map, true)
+}
+
+// checkInsert valida la entidad a insertar, transform indica si se aplican encrypt y cipher a los valores
+func checkInsert(ctx context.Context, schema []Fields, tabla_map Entity, transform bool) (map[string]any, error) {
+	lang := logger.LanguageContext(ctx)
+	ctx = withEntity(ctx, tabla_map, INSERT)
+	var errs ValidationErrors
 	data := make(map[string]any)
+	v := reflect.Indirect(reflect.ValueOf(tabla_map))
 	for _, item := range schema {
-		v := reflect.ValueOf(tabla_map)
 		// fmt.Println("ERRName:", item.NameOriginal)
-		val := v.FieldByName(item.NameOriginal)
+		val := item.FieldValue(v)
 		// fmt.Println("ERR:", val)
 		isNil := val.IsZero()
 		// fmt.Println(isNil, item.NameOriginal)
 		defaultIsNil := item.Default == nil
 		if !isNil {
-			if _, ok := val.Interface().(string); ok {
+			if val.Kind() == reflect.String {
 				if strings.TrimSpace(val.String()) == "" {
 					isNil = true
 				}
 			}
 		}
 
+		if isNil && (item.AutoCreateTime || item.AutoUpdateTime) {
+			data[item.Name] = item.TimeValue()
+			continue
+		}
+
 		if !isNil {
 
-			val, err := validaciones(item, val.Interface())
+			value, err := validaciones(item, val.Interface(), transform)
+			if err == nil {
+				err = customValidations(ctx, item, reflect.Indirect(val).Interface())
+			}
 			if err == nil {
-				data[item.Name] = val
+				data[item.Name] = value
 			} else {
-				errs = append(errs, fmt.Sprintf("Se encontró fallas al validar el campo %s: (%s)", item.Description, err.Error()))
+				errs = append(errs, fieldRuleErrors(lang, item, err)...)
 			}
 		} else {
 			if !defaultIsNil {
 				data[item.Name] = item.Default
 			} else {
 				if item.Required || item.PrimaryKey {
-					errs = append(errs, fmt.Sprintf("El campo %s: (es Requerido)", item.Description))
+					errs = append(errs, fieldError(lang, item, newRuleError("required", "validate.required")))
 				}
 			}
 		}
 
 	}
+	errs = append(errs, crossValidations(ctx, lang, schema, tabla_map, nil)...)
 	if len(errs) > 0 {
-		return nil, errors.New(strings.Join(errs, ", "))
+		return nil, errs
 	} else {
 		return data, nil
 	}
 }
 
-func CheckUpdateGeneric(schemas []Fields, tabla_map any) (map[string]any, error) {
-	var errs []string
+/*
+CheckUpdateGeneric valida los campos de la entidad que se actualizaran.
+
+Los campos con valor cero se omiten, salvo los indicados en set (nombre de la columna o del campo),
+que se actualizan aunque su valor sea 0, false o vació; si el campo es un puntero nil se actualiza a NULL.
+
+	Parámetros
+		* schemas {[]Fields}: campos generados con la acción UPDATE
+		* tabla_map {any}: entidad con los nuevos valores (valor o puntero)
+		* set {...string}: campos que se actualizan explícitamente
+	Return
+		- (map[string]any) columnas y valores a actualizar
+		- (error) fallas de validación
+*/
+func CheckUpdateGeneric(schemas []Fields, tabla_map any, set ...string) (map[string]any, error) {
+	return CheckUpdateGenericContext(context.Background(), schemas, tabla_map, set...)
+}
+
+// CheckUpdateGenericContext es CheckUpdateGeneric con los mensajes en el idioma del contexto (logger.WithLanguage)
+func CheckUpdateGenericContext(ctx context.Context, schemas []Fields, tabla_map any, set ...string) (map[string]any, error) {
+	return checkUpdate(ctx, schemas, tabla_map, set, true)
+}
+
+// checkUpdate valida la entidad a actualizar, transform indica si se aplican encrypt y cipher a los valores
+func checkUpdate(ctx context.Context, schemas []Fields, tabla_map any, set []string, transform bool) (map[string]any, error) {
+	lang := logger.LanguageContext(ctx)
+	ctx = withEntity(ctx, tabla_map, UPDATE)
+	var errs ValidationErrors
 	data := make(map[string]any)
+	for _, name := range set {
+		if !slices.ContainsFunc(schemas, func(f Fields) bool { return f.Update && explicitField(f, name) }) {
+			errs = append(errs, fieldError(lang, Fields{NameOriginal: name, JSON: name, Description: name}, newRuleError("update", "validate.update")))
+		}
+	}
+	v := reflect.Indirect(reflect.ValueOf(tabla_map))
 	for _, item := range schemas {
-		if item.NameOriginal == "Conditions" {
+		if item.NameOriginal == "Conditions" || item.Version || item.SoftDelete || item.AutoUpdateTime {
 			continue
 		}
-		v := reflect.ValueOf(tabla_map)
-		val := v.FieldByName(item.NameOriginal)
+		val := item.FieldValue(v)
 		isNil := val.IsValid()
 		if isNil {
 			isNil = val.IsZero()
 		}
 
+		explicit := slices.ContainsFunc(set, func(name string) bool { return explicitField(item, name) })
+		if explicit && val.Kind() == reflect.Pointer && val.IsNil() {
+			if item.Required || item.PrimaryKey || item.ArithmeticOperations != None {
+				errs = append(errs, fieldError(lang, item, newRuleError("null", "validate.null")))
+			} else {
+				data[item.Name] = nil
+			}
+			continue
+		}
+
 		// fmt.Println(isNil, ":", val.Interface(), ":", item.NameOriginal)
-		if !isNil {
+		if !isNil || explicit {
 			if item.Update {
 				value := val.Interface()
 
 				if x, ok := value.(string); ok {
 					if strings.TrimSpace(x) == "" {
 						if !item.Empty {
-							errs = append(errs, fmt.Sprintf("El campo %s no puede estar vació\n", item.Description))
+							errs = append(errs, fieldError(lang, item, newRuleError("empty", "validate.empty")))
 						}
 					}
 				}
 
 				var val interface{}
-				val, err := validaciones(item, value)
+				val, err := validaciones(item, value, transform)
+				if err == nil {
+					err = customValidations(ctx, item, reflect.Indirect(reflect.ValueOf(value)).Interface())
+				}
+
+				if err == nil && item.ArithmeticOperations == Divide && val != nil && reflect.ValueOf(val).IsZero() {
+					err = []ruleError{newRuleError("divide", "validate.divide")}
+				}
 
 				if err == nil {
 					keyName := item.Name
@@ -104,26 +187,49 @@ func CheckUpdateGeneric(schemas []Fields, tabla_map any) (map[string]any, error)
 
 					data[keyName] = val
 				} else {
-					errs = append(errs, fmt.Sprintf("Se encontró fallas al validar el campo %s \n %s\n", item.Description, err.Error()))
+					errs = append(errs, fieldRuleErrors(lang, item, err)...)
 				}
 			} else {
-				errs = append(errs, fmt.Sprintf("El campo %s no puede ser modificado\n", item.Description))
+				errs = append(errs, fieldError(lang, item, newRuleError("update", "validate.update")))
 			}
 		}
 	}
 
+	errs = append(errs, crossValidations(ctx, lang, schemas, tabla_map, set)...)
 	if len(errs) > 0 {
-		return nil, errors.New(strings.Join(errs, ", "))
+		return nil, errs
 	} else {
 		return data, nil
 	}
 }
 
+// explicitField indica si name (columna o nombre del campo) corresponde al campo
+func explicitField(item Fields, name string) bool {
+	return strings.EqualFold(name, item.Name) || name == item.NameOriginal
+}
+
 func CheckWhereGeneric(schemas []Fields, table_where ...Where) ([]Where, error) {
+	return CheckWhereGenericContext(context.Background(), schemas, table_where...)
+}
+
+/*
+CheckWhereGenericContext valida las condiciones de una sentencia contra los campos where y primaryKey del esquema.
+
+	Parámetros
+		* ctx {context.Context}: contexto con el idioma de los mensajes (logger.WithLanguage)
+		* schemas {[]Fields}: campos del esquema
+		* table_where {...Where}: condiciones de la sentencia
+	Return
+		- ([]Where) condiciones con el operador y la cláusula normalizados
+		- (error) mensajes del catalogo (claves where.*) unidos con ", "
+*/
+func CheckWhereGenericContext(ctx context.Context, schemas []Fields, table_where ...Where) ([]Where, error) {
+	lang := logger.LanguageContext(ctx)
 	var errs []string
 	usePrimaryKey := false
 	fieldNotExistLent := 0
-	for _, item := range table_where {
+	table_where = slices.Clone(table_where)
+	for k, item := range table_where {
 		fieldExist := false
 		for _, v := range schemas {
 			// fmt.Println(item.Field, " : ", v.Name)
@@ -134,13 +240,13 @@ func CheckWhereGeneric(schemas []Fields, table_where ...Where) ([]Where, error)
 					}
 					if x, ok := item.Value.(string); ok {
 						if strings.TrimSpace(x) == "" {
-							errs = append(errs, fmt.Sprintf("El campo %s no puede ser utilizado de esta forma\n", v.Description))
+							errs = append(errs, logger.Message(lang, "where.field", v.Description))
 						}
 
 					}
 
 				} else {
-					errs = append(errs, fmt.Sprintf("El campo %s no puede ser utilizado de esta forma\n", v.Description))
+					errs = append(errs, logger.Message(lang, "where.field", v.Description))
 				}
 				fieldExist = true
 				continue
@@ -151,10 +257,17 @@ func CheckWhereGeneric(schemas []Fields, table_where ...Where) ([]Where, error)
 			fieldNotExistLent++
 		}
 
+		operator, clauseName, err := checkCondition(lang, item, k == 0)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		table_where[k].Condition = operator
+		table_where[k].Clause = clauseName
 	}
 
 	if fieldNotExistLent > 0 {
-		errs = append(errs, "Uno o más campos enviados no son válidos.")
+		errs = append(errs, logger.Message(lang, "where.unknown_field"))
 	}
 
 	existePrimaryKey := slices.ContainsFunc(schemas, func(u Fields) bool {
@@ -162,7 +275,7 @@ func CheckWhereGeneric(schemas []Fields, table_where ...Where) ([]Where, error)
 	})
 
 	if !usePrimaryKey || !existePrimaryKey {
-		errs = append(errs, "Existen campos obligatorios sin information")
+		errs = append(errs, logger.Message(lang, "where.primary_key"))
 	}
 
 	if len(errs) > 0 {
@@ -172,53 +285,160 @@ func CheckWhereGeneric(schemas []Fields, table_where ...Where) ([]Where, error)
 	}
 }
 
-func validaciones(item Fields, value any) (any, error) {
-	var val any
-	var err error
-
-	switch v := value.(type) {
-	case string:
-		val, err = caseString(v, item.ValidateType.(TypeStrings))
-	case *string:
-		val, err = caseString(*v, item.ValidateType.(TypeStrings))
-	case float64:
-		val, err = caseFloat(v, item.ValidateType.(TypeFloat64))
-	case *float64:
-		val, err = caseFloat(*v, item.ValidateType.(TypeFloat64))
-	case uint64:
-		val, err = caseUint(v, item.ValidateType.(TypeUint64))
-	case *uint64:
-		val, err = caseUint(*v, item.ValidateType.(TypeUint64))
-	case int64:
-		val, err = caseInt(v, item.ValidateType.(TypeInt64))
-	case *int64:
-		val, err = caseInt(*v, item.ValidateType.(TypeInt64))
-	case *time.Time:
-		val = v // Los tiempos se suelen manejar como punteros directamente
-	case bool:
-		val = v
-	case *bool:
-		val = *v // Ahora es seguro obtener el contenido
+// whereOperators son los operadores aceptados en las condiciones de Where, los mismos que acepta el query builder
+var whereOperators = []string{"=", "<>", ">", ">=", "<", "<=", "LIKE", "IN", "NOT IN", "BETWEEN", "NOT BETWEEN", "IS NULL", "IS NOT NULL", "= ANY"}
+
+// whereClauses son las cláusulas que unen las condiciones de Where
+var whereClauses = []string{"WHERE", "AND", "OR"}
+
+/*
+checkCondition valida la cláusula y el operador de una condición contra los operadores del query builder
+y que el valor corresponda al operador: lista para IN y = ANY, dos valores para BETWEEN y ningún valor para IS NULL.
+La cláusula de la primera condición no se escribe en la sentencia, si esta vacía se toma como WHERE;
+WHERE solo se acepta en la primera condición, las siguientes deben unirse con AND u OR.
+
+	Return
+		- (string) operador normalizado (mayúsculas y un espacio entre palabras)
+		- (string) cláusula normalizada
+		- (error) si la cláusula, el operador o el valor no son validos
+*/
+func checkCondition(lang logger.Lang, item Where, first bool) (string, string, error) {
+	operator := strings.ToUpper(strings.Join(strings.Fields(item.Condition), " "))
+	if !slices.Contains(whereOperators, operator) {
+		return "", "", logger.Errorf(lang, "where.condition", item.Condition, item.Field)
+	}
+	clauseName := strings.ToUpper(strings.TrimSpace(item.Clause))
+	if clauseName == "" && first {
+		clauseName = "WHERE"
+	}
+	if !slices.Contains(whereClauses, clauseName) || (clauseName == "WHERE" && !first) {
+		return "", "", logger.Errorf(lang, "where.clause", item.Clause, item.Field)
+	}
+
+	length := -1
+	if v := reflect.ValueOf(item.Value); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		if _, isBytes := item.Value.([]byte); !isBytes {
+			length = v.Len()
+		}
+	}
 
+	switch operator {
+	case "IN", "NOT IN", "= ANY":
+		if length <= 0 {
+			return "", "", logger.Errorf(lang, "where.list", item.Field, operator)
+		}
+	case "BETWEEN", "NOT BETWEEN":
+		if length != 2 {
+			return "", "", logger.Errorf(lang, "where.between", item.Field, operator)
+		}
+	case "IS NULL", "IS NOT NULL":
+		if item.Value != nil {
+			return "", "", logger.Errorf(lang, "where.no_value", item.Field, operator)
+		}
 	default:
-		val, err = nil, errors.New("tipo de dato no asignado")
+		if length >= 0 {
+			return "", "", logger.Errorf(lang, "where.no_list", item.Field, operator)
+		}
+	}
+	return operator, clauseName, nil
+}
+
+/*
+validaciones aplica las reglas de validateType al valor del campo, acepta cualquier ancho de entero y flotante,
+string, bool, time.Time, []byte y uuid.UUID, tanto en valor como en puntero (incluye tipos con nombre como `type Estado string`).
+
+	Parámetros
+		* item {Fields}: campo con sus reglas
+		* value {any}: valor del campo
+		* transform {bool}: aplica encrypt y cipher, false solo valida (ValidateInsert, ValidateUpdate)
+	Return
+		- (any) valor normalizado que se envía a la base de datos (los enteros se envían como int64 o uint64 y los flotantes como float64)
+		- ([]ruleError) reglas que fallaron
+*/
+func validaciones(item Fields, value any, transform bool) (any, []ruleError) {
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Type() {
+	case timeType:
+		t := v.Interface().(time.Time)
+		if err := caseDate(t, ruleOf[TypeDate](item)); err != nil {
+			return nil, err
+		}
+		return value, nil // Los tiempos se envían como llegan (valor o puntero)
+	case uuidType:
+		u := v.Interface().(uuid.UUID)
+		if err := caseUUID(u, ruleOf[TypeUUID](item)); err != nil {
+			return nil, err
+		}
+		return u, nil
 	}
 
-	return val, err
+	switch v.Kind() {
+	case reflect.String:
+		return caseString(v.String(), ruleOf[TypeStrings](item), transform)
+	case reflect.Float32, reflect.Float64:
+		return caseFloat(v.Float(), ruleOf[TypeFloat64](item))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return caseInt(v.Int(), ruleOf[TypeInt64](item))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return caseUint(v.Uint(), ruleOf[TypeUint64](item))
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return caseBytes(v.Bytes(), ruleOf[TypeBytes](item))
+		}
+	}
+	return nil, []ruleError{newRuleError("type", "validate.type")}
+}
+
+// ruleOf retorna las reglas de validateType del campo, vacías si el campo no tiene reglas de ese tipo
+func ruleOf[T any](item Fields) T {
+	rules, _ := item.ValidateType.(T)
+	return rules
 }
 
-func caseString(value string, schema TypeStrings) (string, error) {
+// caseString valida y normaliza el texto, transform indica si se aplica encrypt (bcrypt) y cipher después de validar
+func caseString(value string, schema TypeStrings, transform bool) (string, []ruleError) {
 	value = strings.TrimSpace(value)
 	if schema.Expr != nil {
 		if !schema.Expr.MatchString(value) {
-			return "", errors.New("no cumple con las características")
+			return "", []ruleError{{rule: "expr", code: "validate.expr", params: []any{schema.Expr.String()}}}
+		}
+	}
+
+	if schema.Min > 0 {
+		if len(value) < schema.Min {
+			return "", []ruleError{newRuleError("min", "validate.min.string", schema.Min)}
+		}
+	}
+
+	if schema.Max > 0 {
+		if len(value) > schema.Max {
+			return "", []ruleError{newRuleError("max", "validate.max.string", schema.Max)}
 		}
 	}
 
+	if schema.UpperCase {
+		value = strings.ToUpper(value)
+	} else if schema.LowerCase {
+		value = strings.ToLower(value)
+	}
+
+	if !transform {
+		return value, nil
+	}
+
 	if schema.Encriptar {
 		result, err := bcrypt.GenerateFromPassword([]byte(value), 13)
 		if err != nil {
-			return value, err
+			return value, []ruleError{{rule: "encrypt", code: "validate.encrypt", args: []any{err.Error()}}}
 		}
 		value = string(result)
 		return value, nil
@@ -227,92 +447,98 @@ func caseString(value string, schema TypeStrings) (string, error) {
 	if schema.Cifrar {
 		hash, err := utils.AesEncrypt_PHP([]byte(value), configs.KeyCrypto())
 		if err != nil {
-			return value, err
+			return value, []ruleError{{rule: "cipher", code: "validate.cipher", args: []any{err.Error()}}}
 		}
 		value = hash
 		return value, nil
 	}
 
-	if schema.Min > 0 {
-		if len(value) < schema.Min {
-			return "", fmt.Errorf("no Cumple los caracteres mínimos que debe tener (%v)", schema.Min)
-		}
-	}
+	return value, nil
+}
 
-	if schema.Max > 0 {
-		if len(value) > schema.Max {
-			return "", fmt.Errorf("no Cumple los caracteres máximos que debe tener (%v)", schema.Max)
-		}
+func caseDate(value time.Time, schema TypeDate) []ruleError {
+	var err []ruleError
+	if !schema.Min.IsZero() && value.Before(schema.Min) {
+		err = append(err, newRuleError("min", "validate.min.date", schema.Min.Format(time.DateOnly)))
 	}
-
-	if schema.UpperCase {
-		value = strings.ToUpper(value)
-	} else if schema.LowerCase {
-		value = strings.ToLower(value)
+	if !schema.Max.IsZero() && value.After(schema.Max) {
+		err = append(err, newRuleError("max", "validate.max.date", schema.Max.Format(time.DateOnly)))
 	}
+	return err
+}
 
+func caseBytes(value []byte, schema TypeBytes) ([]byte, []ruleError) {
+	if schema.Min > 0 && len(value) < schema.Min {
+		return nil, []ruleError{newRuleError("min", "validate.min.bytes", schema.Min)}
+	}
+	if schema.Max > 0 && len(value) > schema.Max {
+		return nil, []ruleError{newRuleError("max", "validate.max.bytes", schema.Max)}
+	}
 	return value, nil
 }
 
-func caseDate(value *time.Time, _ TypeDate) (*time.Time, error) {
-	return value, nil
+func caseUUID(value uuid.UUID, schema TypeUUID) []ruleError {
+	if schema.Version > 0 && int(value.Version()) != schema.Version {
+		return []ruleError{newRuleError("version", "validate.version.uuid", schema.Version)}
+	}
+	return nil
 }
 
-func caseFloat(value float64, schema TypeFloat64) (float64, error) {
-	var err []string
+func caseFloat(value float64, schema TypeFloat64) (float64, []ruleError) {
+	var err []ruleError
 	if schema.Menor != 0 {
 		if value <= schema.Menor {
-			err = append(err, fmt.Sprintf("No puede se menor a %f", schema.Menor))
+			err = append(err, newRuleError("menor", "validate.menor", schema.Menor))
 		}
 	}
 	if schema.Mayor != 0 {
 		if value >= schema.Mayor {
-			err = append(err, fmt.Sprintf("No puede se mayor a %f", schema.Mayor))
+			err = append(err, newRuleError("mayor", "validate.mayor", schema.Mayor))
 		}
 	}
 	if !schema.Negativo {
 		if value < float64(0) {
-			err = append(err, "No puede ser negativo")
+			err = append(err, newRuleError("negative", "validate.negative"))
 		}
 	}
 	if schema.Porcentaje {
 		value = value / float64(100)
 	}
 	if len(err) > 0 {
-		return 0, errors.New(strings.Join(err, ", "))
+		return 0, err
 	} else {
 		return value, nil
 	}
 }
 
-func caseInt(value int64, schema TypeInt64) (int64, error) {
-	var err []string
+func caseInt(value int64, schema TypeInt64) (int64, []ruleError) {
+	var err []ruleError
 	if !schema.Negativo {
 		if value < int64(0) {
-			err = append(err, "No puede ser negativo")
+			err = append(err, newRuleError("negative", "validate.negative"))
 		}
 	}
 	if schema.Min != 0 {
 		if value < schema.Min {
-			err = append(err, fmt.Sprintf("No puede se menor a %d", schema.Min))
+			err = append(err, newRuleError("min", "validate.min", schema.Min))
 		}
 	}
 	if schema.Max != 0 {
 		if value > schema.Max {
-			err = append(err, fmt.Sprintf("No puede se mayor a %d", schema.Max))
+			err = append(err, newRuleError("max", "validate.max", schema.Max))
 		}
 	}
 	if len(err) > 0 {
-		return int64(0), errors.New(strings.Join(err, ", "))
+		return int64(0), err
 	} else {
 		return value, nil
 	}
 }
 
-func caseUint(value uint64, t TypeUint64) (uint64, error) {
+func caseUint(value uint64, t TypeUint64) (uint64, []ruleError) {
 	if t.Max > 0 {
 		if value > t.Max {
-			return 0, errors.New("no esta en el rango permitido")
+			return 0, []ruleError{{rule: "max", code: "validate.max.uint", params: []any{t.Max}}}
 		}
 	}
 	return value, nil
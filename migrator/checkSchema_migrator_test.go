@@ -0,0 +1,126 @@
+package migrator_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/deybin/pgorm/logger"
+	"github.com/deybin/pgorm/migrator"
+	"github.com/google/uuid"
+)
+
+type scalarItem struct {
+	Id      uuid.UUID `json:"id" validate:"primaryKey;required" validateType:"version=4"`
+	Level   int       `json:"level" validate:"required;update" validateType:"min=1;max=10"`
+	Port    uint16    `json:"port" validate:"update" validateType:"max=9000"`
+	Ratio   float32   `json:"ratio" validate:"update"`
+	Active  *bool     `json:"active" validate:"update"`
+	Payload []byte    `json:"payload" validate:"update" validateType:"max=4"`
+	Read_at time.Time `json:"read_at" validate:"update" validateType:"min=2020-01-01"`
+}
+
+func (e scalarItem) Name() string      { return "scalar_items" }
+func (e scalarItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e scalarItem) Values() []any     { return migrator.EntityValues(e) }
+
+func TestCheckInsert_ScalarTypes(t *testing.T) {
+	fields := schema[scalarItem]{}.ParseInsert()
+	active := false
+	valid := scalarItem{Id: uuid.New(), Level: 3, Port: 8080, Ratio: 0.5, Active: &active, Payload: []byte{1, 2}, Read_at: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	data, err := migrator.CheckInsertGeneric(fields, valid)
+	if err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	if data["level"] != int64(3) || data["port"] != uint64(8080) || data["active"] != false || len(data["payload"].([]byte)) != 2 {
+		t.Errorf("valores inesperados: %v", data)
+	}
+
+	invalid := scalarItem{Id: uuid.Must(uuid.NewV7()), Level: 11, Port: 9001, Ratio: -1, Payload: []byte{1, 2, 3, 4, 5}, Read_at: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)}
+	_, err = migrator.CheckInsertGeneric(fields, invalid)
+	var verrs migrator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("se esperaba ValidationErrors: %v", err)
+	}
+	rules := rulesOf(verrs)
+	expected := map[string]string{"id": "version", "level": "max", "port": "max", "ratio": "negative", "payload": "max", "read_at": "min"}
+	for field, rule := range expected {
+		if rules[field] != rule {
+			t.Errorf("se esperaba la regla %s en %s: %+v", rule, field, verrs)
+		}
+	}
+}
+
+type benchItem struct {
+	Id     string  `validate:"primaryKey;required"`
+	Nombre string  `validate:"required;update" validateType:"min=3;max=50;case=lowercase"`
+	Price  float64 `validate:"required;update"`
+	Stock  int64   `validate:"update;sum" validateType:"max=1000"`
+	Code   string  `validate:"where" validateType:"max=20"`
+	Email  string  `validate:"update" validateType:"case=lowercase;expr=^[a-z0-9.]+@[a-z0-9]+(\\.[a-z0-9]+)+$"`
+}
+
+func (e benchItem) Name() string      { return "bench_items" }
+func (e benchItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e benchItem) Values() []any     { return migrator.EntityValues(e) }
+
+func BenchmarkCheckInsert(b *testing.B) {
+	s := schema[benchItem]{}
+	entity := &benchItem{Id: "1", Nombre: "Producto", Price: 10.5, Stock: 20, Code: "ABC", Email: "juan@mail.com"}
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := migrator.CheckInsertGeneric(s.ParseInsert(), entity); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCheckUpdate(b *testing.B) {
+	s := schema[benchItem]{}
+	entity := benchItem{Nombre: "Producto", Price: 10.5, Stock: 20}
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := migrator.CheckUpdateGeneric(s.ParseUpdate(), entity); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCheckWhere_Language(t *testing.T) {
+	ctx := logger.WithLanguage(context.Background(), logger.EN)
+	_, err := migrator.CheckWhereGenericContext(ctx, schema[whereLangItem]{}.ParseDelete(), migrator.Where{Condition: "BETWEEN", Field: "id", Value: []any{"1"}})
+	if err == nil || err.Error() != "The field id needs two values for the condition BETWEEN" {
+		t.Errorf("mensaje inesperado: %v", err)
+	}
+}
+
+type whereLangItem struct {
+	Id string `validate:"primaryKey"`
+}
+
+func (e whereLangItem) Name() string      { return "where_lang_items" }
+func (e whereLangItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e whereLangItem) Values() []any     { return migrator.EntityValues(e) }
+
+type divideItem struct {
+	Id    string  `json:"id" validate:"primaryKey"`
+	Ratio float64 `json:"ratio" tag:"ratio" validate:"update;divide"`
+}
+
+func (e divideItem) Name() string      { return "divide_items" }
+func (e divideItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e divideItem) Values() []any     { return migrator.EntityValues(e) }
+
+func TestCheckUpdate_DivideByZero(t *testing.T) {
+	ctx := logger.WithLanguage(context.Background(), logger.EN)
+	_, err := migrator.CheckUpdateGenericContext(ctx, schema[divideItem]{}.ParseUpdate(), divideItem{}, "ratio")
+	var verrs migrator.ValidationErrors
+	if !errors.As(err, &verrs) || rulesOf(verrs)["ratio"] != "divide" || verrs[0].Message != "The field ratio failed validation: (cannot be divided by zero)" {
+		t.Errorf("se esperaba la falla divide en el campo: %v", err)
+	}
+
+	if data, err := migrator.CheckUpdateGeneric(schema[divideItem]{}.ParseUpdate(), divideItem{Ratio: 2}); err != nil || data["ADD_ratio_DIVIDE"] != 2.0 {
+		t.Errorf("resultado inesperado: %v %v", data, err)
+	}
+}
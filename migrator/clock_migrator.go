@@ -0,0 +1,95 @@
+package migrator
+
+import (
+	"log/slog"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// TimeFormat indica como se guarda la fecha en los campos autoCreateTime, autoUpdateTime y softDelete
+type TimeFormat uint8
+
+const (
+	Timestamp TimeFormat = iota // time.Time (columnas timestamp)
+	Unix                        // segundos desde epoch
+	UnixMilli                   // milisegundos desde epoch
+	UnixNano                    // nanosegundos desde epoch
+)
+
+var (
+	clockMu sync.RWMutex
+	clock   = time.Now
+)
+
+/*
+SetClock reemplaza el reloj usado para los campos de fecha automáticos, permite congelar el tiempo en las pruebas.
+
+	Parámetros
+		* now {func() time.Time}: función que devuelve la hora actual, nil restablece time.Now
+*/
+func SetClock(now func() time.Time) {
+	clockMu.Lock()
+	defer clockMu.Unlock()
+	if now == nil {
+		now = time.Now
+	}
+	clock = now
+}
+
+// Now devuelve la hora actual en UTC según el reloj establecido con SetClock
+func Now() time.Time {
+	clockMu.RLock()
+	defer clockMu.RUnlock()
+	return clock().UTC()
+}
+
+// TimeValue devuelve la hora actual en el formato del campo
+func (f Fields) TimeValue() any {
+	now := Now()
+	switch f.TimeFormat {
+	case Unix:
+		return now.Unix()
+	case UnixMilli:
+		return now.UnixMilli()
+	case UnixNano:
+		return now.UnixNano()
+	default:
+		return now
+	}
+}
+
+// timeFormat obtiene el formato de fecha según el tipo del campo, los enteros usan unix (o `milli`/`nano` si se indica en la regla)
+func timeFormat(t reflect.Type, param string) TimeFormat {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch param {
+		case "milli":
+			return UnixMilli
+		case "nano":
+			return UnixNano
+		}
+		return Unix
+	}
+	return Timestamp
+}
+
+// reportedAtcreate evita repetir en el log el aviso de un mismo struct
+var reportedAtcreate sync.Map
+
+/*
+warnLegacyAtcreate avisa una vez por struct que el campo Atcreate sin autoCreateTime está obsoleto.
+
+Antes Atcreate recibía la fecha actual por su nombre, se mantiene ese comportamiento para no romper los modelos
+existentes hasta que agreguen la regla `validate:"autoCreateTime"`.
+*/
+func warnLegacyAtcreate(t reflect.Type) {
+	if _, loaded := reportedAtcreate.LoadOrStore(t, true); loaded {
+		return
+	}
+	slog.Warn("el campo Atcreate sin la regla autoCreateTime está obsoleto, agregue validate:\"autoCreateTime\"", "struct", t.String())
+}
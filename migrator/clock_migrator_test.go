@@ -0,0 +1,67 @@
+package migrator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deybin/pgorm/migrator"
+)
+
+type stampedItem struct {
+	Id         string     `validate:"primaryKey;required"`
+	Total      float64    `validate:"required;update"`
+	Created_at *time.Time `validate:"autoCreateTime"`
+	Updated_at int64      `validate:"autoUpdateTime=milli"`
+}
+
+func (e stampedItem) Name() string      { return "stamped_items" }
+func (e stampedItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e stampedItem) Values() []any     { return migrator.EntityValues(e) }
+
+func TestAutoTimestamps(t *testing.T) {
+	frozen := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	migrator.SetClock(func() time.Time { return frozen })
+	defer migrator.SetClock(nil)
+
+	data, err := migrator.CheckInsertGeneric(schema[stampedItem]{}.ParseInsert(), stampedItem{Id: "1", Total: 100})
+	if err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	if data["created_at"] != frozen || data["updated_at"] != frozen.UnixMilli() {
+		t.Errorf("valores inesperados: %v", data)
+	}
+
+	for _, f := range (schema[stampedItem]{}).ParseUpdate() {
+		if f.Name == "updated_at" && (!f.AutoUpdateTime || f.TimeValue() != frozen.UnixMilli()) {
+			t.Errorf("campo autoUpdateTime inesperado: %+v", f)
+		}
+	}
+}
+
+type narrowStampItem struct {
+	Id       string     `validate:"primaryKey;required"`
+	Created  int32      `validate:"autoCreateTime"`
+	Updated  uint16     `validate:"autoUpdateTime"`
+	Atcreate *time.Time `validate:"required"`
+}
+
+func (e narrowStampItem) Name() string      { return "narrow_stamp_items" }
+func (e narrowStampItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e narrowStampItem) Values() []any     { return migrator.EntityValues(e) }
+
+func TestAutoTimestamps_NarrowIntsAndLegacyAtcreate(t *testing.T) {
+	frozen := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	migrator.SetClock(func() time.Time { return frozen })
+	defer migrator.SetClock(nil)
+
+	data, err := migrator.CheckInsertGeneric(schema[narrowStampItem]{}.ParseInsert(), narrowStampItem{Id: "1"})
+	if err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	if data["created"] != frozen.Unix() || data["updated"] != frozen.Unix() {
+		t.Errorf("los enteros de cualquier tamaño deben usar unix: %v", data)
+	}
+	if data["atcreate"] != frozen {
+		t.Errorf("Atcreate sin autoCreateTime debe recibir la fecha actual: %v", data)
+	}
+}
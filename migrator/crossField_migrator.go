@@ -0,0 +1,289 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/deybin/pgorm/logger"
+)
+
+/*
+CrossRule es una regla que relaciona el campo con otro campo de la entidad, se declara en la etiqueta validate:
+
+	required_if=Campo:valor      el campo es requerido si Campo tiene el valor
+	required_unless=Campo:valor  el campo es requerido salvo que Campo tenga el valor
+	required_with=Campo          el campo es requerido si Campo tiene valor
+	required_without=Campo       el campo es requerido si Campo no tiene valor
+	eqfield, nefield, gtfield, gtefield, ltfield, ltefield=Campo  compara el valor con el de Campo (números, textos y fechas)
+
+Campo puede ser el nombre del campo en el struct, el nombre de la columna o el de la etiqueta json.
+*/
+type CrossRule struct {
+	Name  string
+	Field string
+	Value string
+}
+
+/*
+EntityValidator lo implementan las entidades que tienen reglas que no se pueden expresar con etiquetas,
+Validate se ejecuta después de validar los campos en CheckInsertGeneric y CheckUpdateGeneric.
+Si retorna ValidationErrors o ValidationError se agregan a las fallas de los campos, cualquier otro error se agrega con la regla `validate`.
+En una actualización la entidad solo contiene los valores que se actualizaran, la acción se obtiene con ActionContext.
+Si Validate tiene receptor puntero y la entidad se envía por valor se ejecuta sobre una copia.
+*/
+type EntityValidator interface {
+	Validate(ctx context.Context) error
+}
+
+// crossRuleNames son las reglas entre campos de la etiqueta validate, el valor indica si el parámetro es `Campo:valor`
+var crossRuleNames = map[string]bool{
+	"required_if": true, "required_unless": true, "required_with": false, "required_without": false,
+	"eqfield": false, "nefield": false, "gtfield": false, "gtefield": false, "ltfield": false, "ltefield": false,
+}
+
+type actionKey struct{}
+
+// ActionContext retorna la acción (INSERT o UPDATE) de la validación en curso
+func ActionContext(ctx context.Context) Actions {
+	action, _ := ctx.Value(actionKey{}).(Actions)
+	return action
+}
+
+// parseCrossRules busca las reglas entre campos en la etiqueta validate y verifica que el campo referenciado exista en el struct t
+func parseCrossRules(t reflect.Type, rules tagRules) ([]CrossRule, map[string]string) {
+	var cross []CrossRule
+	errs := make(map[string]string)
+	for _, rule := range rules {
+		withValue, ok := crossRuleNames[rule.name]
+		if !ok {
+			continue
+		}
+		field, value, hasValue := strings.Cut(rule.param, ":")
+		field, value = strings.TrimSpace(field), strings.TrimSpace(value)
+		switch {
+		case field == "":
+			errs[rule.name] = "necesita el campo a comparar"
+		case withValue && !hasValue:
+			errs[rule.name] = "el parámetro debe tener el formato Campo:valor"
+		case !withValue && hasValue:
+			errs[rule.name] = "el parámetro solo debe tener el nombre del campo"
+		default:
+			if _, _, exists := otherField(reflect.New(t).Elem(), field); !exists {
+				errs[rule.name] = fmt.Sprintf("el campo %s no existe", field)
+				continue
+			}
+			cross = append(cross, CrossRule{Name: rule.name, Field: field, Value: value})
+		}
+	}
+	return cross, errs
+}
+
+/*
+crossValidations evalúa las reglas entre campos y el método Validate de la entidad.
+
+	Parámetros
+		* schema {[]Fields}: campos de la acción
+		* entity {any}: entidad validada
+		* set {[]string}: campos enviados explícitamente en una actualización, cuentan como presentes aunque su valor sea cero
+
+En una actualización las reglas required_* solo se evalúan si el campo o el campo referenciado tienen valor o están en set,
+la entidad solo contiene los valores que se actualizaran y los demás campos no se deben exigir.
+*/
+func crossValidations(ctx context.Context, lang logger.Lang, schema []Fields, entity any, set []string) ValidationErrors {
+	var errs ValidationErrors
+	v := reflect.Indirect(reflect.ValueOf(entity))
+	update := ActionContext(ctx) == UPDATE
+	for _, item := range schema {
+		if len(item.CrossRules) == 0 {
+			continue
+		}
+		value := item.FieldValue(v)
+		present := isPresent(value) || slices.ContainsFunc(set, func(name string) bool { return explicitField(item, name) })
+		for _, rule := range item.CrossRules {
+			other, description, ok := otherField(v, rule.Field)
+			if !ok {
+				errs = append(errs, fieldError(lang, item, ruleError{rule: rule.Name, code: "validate.field.unknown", params: []any{rule.Field}, args: []any{rule.Field}}))
+				continue
+			}
+			otherPresent := isPresent(other) || inSet(set, v.Type(), rule.Field)
+			if update && strings.HasPrefix(rule.Name, "required_") && !present && !otherPresent {
+				continue
+			}
+			if e, failed := crossRule(rule, present, value, otherPresent, other, description); failed {
+				errs = append(errs, fieldError(lang, item, e))
+			}
+		}
+	}
+
+	if validator, ok := entityValidator(entity, v); ok {
+		errs = append(errs, entityErrors(validator.Validate(ctx))...)
+	}
+	return errs
+}
+
+// entityValidator obtiene el EntityValidator de la entidad, si Validate tiene receptor puntero y la entidad se recibió por valor se usa una copia
+func entityValidator(entity any, v reflect.Value) (EntityValidator, bool) {
+	if validator, ok := entity.(EntityValidator); ok {
+		return validator, true
+	}
+	if !v.IsValid() {
+		return nil, false
+	}
+	if !v.CanAddr() {
+		copied := reflect.New(v.Type())
+		copied.Elem().Set(v)
+		v = copied.Elem()
+	}
+	validator, ok := v.Addr().Interface().(EntityValidator)
+	return validator, ok
+}
+
+// inSet indica si el campo referenciado por name (campo del struct, columna o json) esta en los campos enviados en set
+func inSet(set []string, t reflect.Type, name string) bool {
+	field, ok := structField(t, name)
+	return ok && slices.ContainsFunc(set, func(s string) bool { return strings.EqualFold(s, field.Name) })
+}
+
+// indirectValue retorna el valor del campo, nil si es un puntero nil
+func indirectValue(v reflect.Value) any {
+	if v = reflect.Indirect(v); !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
+
+func crossRule(rule CrossRule, present bool, value reflect.Value, otherPresent bool, other reflect.Value, description string) (ruleError, bool) {
+	params := []any{rule.Field}
+	if rule.Value != "" {
+		params = append(params, rule.Value)
+	}
+	failure := ruleError{rule: rule.Name, code: "validate." + rule.Name, params: params, args: []any{description}}
+	if rule.Value != "" {
+		failure.args = append(failure.args, rule.Value)
+	}
+
+	switch rule.Name {
+	case "required_if":
+		return failure, !present && otherPresent && fmt.Sprint(indirectValue(other)) == rule.Value
+	case "required_unless":
+		return failure, !present && (!otherPresent || fmt.Sprint(indirectValue(other)) != rule.Value)
+	case "required_with":
+		return failure, !present && otherPresent
+	case "required_without":
+		return failure, !present && !otherPresent
+	}
+
+	a, b := reflect.Indirect(value), reflect.Indirect(other)
+	if !present || !otherPresent || !a.IsValid() || !b.IsValid() {
+		return failure, false
+	}
+	cmp, ok := compareValues(a, b)
+	if !ok {
+		return ruleError{rule: rule.Name, code: "validate.type", params: params}, true
+	}
+	switch rule.Name {
+	case "eqfield":
+		return failure, cmp != 0
+	case "nefield":
+		return failure, cmp == 0
+	case "gtfield":
+		return failure, cmp <= 0
+	case "gtefield":
+		return failure, cmp < 0
+	case "ltfield":
+		return failure, cmp >= 0
+	case "ltefield":
+		return failure, cmp > 0
+	}
+	return failure, false
+}
+
+// isPresent indica si el campo tiene valor (no es cero, nil ni un texto vació)
+func isPresent(value reflect.Value) bool {
+	if !value.IsValid() || value.IsZero() {
+		return false
+	}
+	if value = reflect.Indirect(value); value.Kind() == reflect.String {
+		return strings.TrimSpace(value.String()) != ""
+	}
+	return true
+}
+
+// otherField busca el campo referenciado por una regla por su nombre en el struct, columna o etiqueta json
+func otherField(v reflect.Value, name string) (reflect.Value, string, bool) {
+	field, ok := structField(v.Type(), name)
+	if !ok {
+		return reflect.Value{}, "", false
+	}
+	description := field.Tag.Get("tag")
+	if description == "" {
+		description = name
+	}
+	return v.FieldByIndex(field.Index), description, true
+}
+
+// structField busca el campo del struct t por su nombre, columna o etiqueta json
+func structField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Name == name || strings.EqualFold(field.Name, name) || jsonName(field) == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// compareValues compara dos números, textos o fechas, retorna false si los valores no se pueden comparar
+func compareValues(a reflect.Value, b reflect.Value) (int, bool) {
+	if a.Type() == timeType && b.Type() == timeType {
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time)), true
+	}
+	if a.Kind() == reflect.String && b.Kind() == reflect.String {
+		return strings.Compare(a.String(), b.String()), true
+	}
+	x, okA := numberOf(a)
+	y, okB := numberOf(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	switch {
+	case x < y:
+		return -1, true
+	case x > y:
+		return 1, true
+	}
+	return 0, true
+}
+
+func numberOf(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// entityErrors convierte el error de Validate en fallas de validación
+func entityErrors(err error) ValidationErrors {
+	if err == nil {
+		return nil
+	}
+	var errs ValidationErrors
+	if errors.As(err, &errs) {
+		return errs
+	}
+	var single ValidationError
+	if errors.As(err, &single) {
+		return ValidationErrors{single}
+	}
+	return ValidationErrors{{Rule: "validate", Message: err.Error()}}
+}
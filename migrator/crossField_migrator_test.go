@@ -0,0 +1,95 @@
+package migrator_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/deybin/pgorm/migrator"
+)
+
+type contractItem struct {
+	Id         string    `json:"id" validate:"primaryKey;required"`
+	Doc_type   string    `json:"doc_type" validate:"required;update"`
+	Ruc        string    `json:"ruc" validate:"update;required_if=Doc_type:company"`
+	Start_date time.Time `json:"start_date" validate:"required;update"`
+	End_date   time.Time `json:"end_date" validate:"update;gtfield=Start_date"`
+	Amount     float64   `json:"amount" validate:"update"`
+}
+
+func (e *contractItem) Validate(ctx context.Context) error {
+	if migrator.ActionContext(ctx) == migrator.INSERT && e.Amount == 0 && e.Doc_type == "company" {
+		return errors.New("los contratos de empresa deben tener monto")
+	}
+	return nil
+}
+
+func (e contractItem) Name() string      { return "contract_items" }
+func (e contractItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e contractItem) Values() []any     { return migrator.EntityValues(e) }
+
+func TestCrossFieldRules(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fields := schema[contractItem]{}.ParseInsert()
+	valid := &contractItem{Id: "1", Doc_type: "company", Ruc: "20123456789", Start_date: start, End_date: start.AddDate(1, 0, 0), Amount: 10}
+	if _, err := migrator.CheckInsertGeneric(fields, valid); err != nil {
+		t.Errorf("no se esperaba este error: %v", err)
+	}
+
+	invalid := &contractItem{Id: "1", Doc_type: "company", Start_date: start, End_date: start.AddDate(-1, 0, 0)}
+	_, err := migrator.CheckInsertGeneric(fields, invalid)
+	var verrs migrator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("se esperaba ValidationErrors: %v", err)
+	}
+	rules := rulesOf(verrs)
+	if rules["ruc"] != "required_if" || rules["end_date"] != "gtfield" || rules[""] != "validate" {
+		t.Errorf("fallas inesperadas: %+v", verrs)
+	}
+
+	// en una actualización las reglas solo comparan los campos enviados
+	if _, err := migrator.CheckUpdateGeneric(schema[contractItem]{}.ParseUpdate(), contractItem{End_date: start}); err != nil {
+		t.Errorf("no se esperaba este error: %v", err)
+	}
+}
+
+type contactItem struct {
+	Id     string `json:"id" validate:"primaryKey;required"`
+	Alias  string `json:"alias" validate:"update"`
+	Email  string `json:"email" validate:"update"`
+	Phone  string `json:"phone" validate:"update;required_with=Email"`
+	Status string `json:"status" validate:"update"`
+	Reason string `json:"reason" validate:"update;required_unless=Status:active"`
+}
+
+func (e contactItem) Name() string      { return "contact_items" }
+func (e contactItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e contactItem) Values() []any     { return migrator.EntityValues(e) }
+
+func TestCrossFieldRules_Update(t *testing.T) {
+	fields := schema[contactItem]{}.ParseUpdate()
+	if _, err := migrator.CheckUpdateGeneric(fields, contactItem{Alias: "ana"}); err != nil {
+		t.Errorf("los campos que no se envían no deben exigirse: %v", err)
+	}
+
+	_, err := migrator.CheckUpdateGeneric(fields, contactItem{Email: "ana@mail.pe"})
+	var verrs migrator.ValidationErrors
+	if !errors.As(err, &verrs) || rulesOf(verrs)["phone"] != "required_with" {
+		t.Errorf("al enviar email se esperaba exigir phone: %v", err)
+	}
+
+	if _, err := migrator.CheckInsertGeneric(schema[contactItem]{}.ParseInsert(), contactItem{Id: "1", Email: "ana@mail.pe", Status: "active"}); err == nil {
+		t.Error("en la inserción se esperaba exigir phone")
+	}
+}
+
+func TestCrossFieldRules_ValidateByValue(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entity := contractItem{Id: "1", Doc_type: "company", Ruc: "20123456789", Start_date: start}
+	_, err := migrator.CheckInsertGeneric(schema[contractItem]{}.ParseInsert(), entity)
+	var verrs migrator.ValidationErrors
+	if !errors.As(err, &verrs) || rulesOf(verrs)[""] != "validate" {
+		t.Errorf("Validate con receptor puntero debe ejecutarse aunque la entidad se envíe por valor: %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package migrator
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/deybin/pgorm/logger"
+)
+
+/*
+ValidationError describe la falla de una regla de validación sobre un campo.
+
+	Rule es la regla que fallo: required, empty, null, update, type, min, max, expr, encrypt, cipher, menor, mayor o negative.
+*/
+type ValidationError struct {
+	Field   string `json:"field"`            //Nombre del campo en el struct
+	JSON    string `json:"name"`             //Nombre del campo en json (etiqueta json), el que conoce el cliente
+	Rule    string `json:"rule"`             //Regla que fallo
+	Params  []any  `json:"params,omitempty"` //Parámetros de la regla, por ejemplo el limite de max
+	Message string `json:"message"`
+
+	code string      // código del mensaje en el catalogo
+	args []any       // argumentos del mensaje
+	lang logger.Lang // idioma del mensaje
+}
+
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+// ValidationErrors son las fallas de validación de una entidad, se obtienen con errors.As sobre el error de CheckInsertGeneric/CheckUpdateGeneric
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, v := range e {
+		messages[i] = v.Message
+	}
+	return strings.Join(messages, ", ")
+}
+
+// ProblemContentType es el content-type de una respuesta ProblemDetails
+const ProblemContentType = "application/problem+json"
+
+// ProblemDetails es la respuesta de error definida en RFC 7807, Errors lista las fallas por campo
+type ProblemDetails struct {
+	Type     string           `json:"type"`
+	Title    string           `json:"title"`
+	Status   int              `json:"status"`
+	Detail   string           `json:"detail,omitempty"`
+	Instance string           `json:"instance,omitempty"`
+	Errors   ValidationErrors `json:"errors,omitempty"`
+}
+
+/*
+Problem convierte las fallas de validación en una respuesta RFC 7807 con estado 422, el titulo usa el idioma de los mensajes.
+
+	Parámetros
+		* instance {string}: uri del recurso que origino el problema, puede ser vació
+*/
+func (e ValidationErrors) Problem(instance string) ProblemDetails {
+	lang := logger.DefaultLanguage()
+	if len(e) > 0 && e[0].lang != "" {
+		lang = e[0].lang
+	}
+	return ProblemDetails{
+		Type:     "about:blank",
+		Title:    logger.Message(lang, "validate.problem"),
+		Status:   http.StatusUnprocessableEntity,
+		Detail:   e.Error(),
+		Instance: instance,
+		Errors:   e,
+	}
+}
+
+// ruleError es la falla de una regla antes de asociarla a un campo, code es el código del mensaje en el catalogo de logger
+type ruleError struct {
+	rule   string
+	code   string
+	params []any
+	args   []any
+}
+
+// newRuleError crea la falla de una regla cuyo mensaje recibe los mismos parámetros de la regla
+func newRuleError(rule string, code string, params ...any) ruleError {
+	return ruleError{rule: rule, code: code, params: params, args: params}
+}
+
+// fieldError crea la falla de validación del campo con el mensaje en el idioma indicado
+func fieldError(lang logger.Lang, item Fields, e ruleError) ValidationError {
+	v := ValidationError{
+		Field:  item.NameOriginal,
+		JSON:   item.JSON,
+		Rule:   e.rule,
+		Params: e.params,
+		code:   e.code,
+		args:   append([]any{item.Description}, e.args...),
+	}
+	return v.localize(lang)
+}
+
+// fieldRuleErrors asocia las fallas de las reglas al campo
+func fieldRuleErrors(lang logger.Lang, item Fields, errs []ruleError) ValidationErrors {
+	result := make(ValidationErrors, 0, len(errs))
+	for _, e := range errs {
+		result = append(result, fieldError(lang, item, e))
+	}
+	return result
+}
+
+func (e ValidationError) localize(lang logger.Lang) ValidationError {
+	if e.code != "" {
+		e.lang = lang
+		e.Message = logger.Message(lang, e.code, e.args...)
+	}
+	return e
+}
+
+// Localize retorna las fallas con los mensajes en el idioma indicado
+func (e ValidationErrors) Localize(lang logger.Lang) ValidationErrors {
+	result := make(ValidationErrors, len(e))
+	for i, v := range e {
+		result[i] = v.localize(lang)
+	}
+	return result
+}
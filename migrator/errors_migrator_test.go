@@ -0,0 +1,50 @@
+package migrator_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/deybin/pgorm/logger"
+	"github.com/deybin/pgorm/migrator"
+)
+
+type errorsItem struct {
+	Id     string  `json:"id" tag:"id" validate:"primaryKey;required"`
+	Nombre string  `json:"nombre" tag:"nombre" validate:"required" validateType:"min=3"`
+	Stock  int64   `json:"stock" tag:"stock" validateType:"max=1000"`
+	Price  float64 `json:"price" tag:"price" validate:"required"`
+}
+
+func (e errorsItem) Name() string      { return "errors_items" }
+func (e errorsItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e errorsItem) Values() []any     { return migrator.EntityValues(e) }
+
+func TestValidationErrors(t *testing.T) {
+	_, err := migrator.CheckInsertGeneric(schema[errorsItem]{}.ParseInsert(), errorsItem{Nombre: "ab", Stock: 2000})
+	var verrs migrator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("se esperaba ValidationErrors: %v", err)
+	}
+	rules := rulesOf(verrs)
+	if rules["id"] != "required" || rules["nombre"] != "min" || rules["stock"] != "max" || rules["price"] != "required" {
+		t.Errorf("fallas inesperadas: %+v", verrs)
+	}
+	if problem := verrs.Problem("/errors_items"); problem.Status != 422 || len(problem.Errors) != len(verrs) {
+		t.Errorf("problem inesperado: %+v", problem)
+	}
+}
+
+func TestValidationErrors_Localized(t *testing.T) {
+	ctx := logger.WithLanguage(context.Background(), logger.EN)
+	_, err := migrator.CheckInsertGenericContext(ctx, schema[errorsItem]{}.ParseInsert(), errorsItem{Id: "1", Nombre: "ab", Price: 1})
+	if err == nil || err.Error() != "The field nombre failed validation: (must have at least 3 characters)" {
+		t.Errorf("mensaje inesperado: %v", err)
+	}
+
+	logger.Register("pt", map[string]string{"validate.min.string": "O campo %s deve ter pelo menos %v caracteres"})
+	var verrs migrator.ValidationErrors
+	if !errors.As(err, &verrs) || verrs.Localize("pt")[0].Message != "O campo nombre deve ter pelo menos 3 caracteres" {
+		t.Errorf("traducción inesperada: %+v", verrs.Localize("pt"))
+	}
+}
@@ -110,14 +110,15 @@ func consultar(query string, database string) []map[string]interface{} {
 
 /*
 type tableModel struct {
-	Document        string    `json:"document" field:"document" tag:"document" validate:"primaryKey,required" validateType:"case=lowercase,min=7,max=11,expr=number"`
-	Nombre          string    `json:"nombre" field:"nombre" tag:"nombre" validate:"required, default=nombre" validateType:"case=lowercase,min=3,max=50,encrypt, cipher"`
-	Password        string    `json:"password" field:"password" tag:"password" validate:"required" validateType:"encrypt"`
-	Code_Secret     string    `json:"code_secret" field:"code_secret" tag:"Código secreto" validate:"required" validateType:"cipher"`
-	FechaNacimiento time.Time `json:"fecha_nacimiento" filed:"fecha_nacimiento" tag:"fecha de nacimiento" validate:"required"`
-	Age             uint64    `json:"age" field:"age" tag:"Edad" validate:"required" validateType:"max=80"`
-	Amount          float64   `json:"amount" field:"amount" tag:"Monto dinerario" validate:"required,update,sum" validateType:"negativo,porcentaje,menor=40.00, mayor=50.00"`
-	Credits         int64     `json:"credits" field:"credits" tag:"Créditos" validate:"required" validateType:"negativo,min=40, max=5"`
+	Document        string    `json:"document" db:"document" tag:"document" validate:"primaryKey;required" validateType:"case=lowercase;min=7;max=11;expr=^[0-9]+$"`
+	Nombre          string    `json:"nombre" db:"nombre" tag:"nombre" validate:"required;default" validateType:"case=lowercase;min=3;max=50"`
+	Password        string    `json:"password" db:"password" tag:"password" validate:"required" validateType:"encrypt"`
+	Code_Secret     string    `json:"code_secret" db:"code_secret" tag:"Código secreto" validate:"required" validateType:"cipher"`
+	FechaNacimiento time.Time `json:"fecha_nacimiento" db:"fecha_nacimiento" tag:"fecha de nacimiento" validate:"required" validateType:"min=1900-01-01"`
+	Age             uint64    `json:"age" db:"age" tag:"Edad" validate:"required" validateType:"max=80"`
+	Amount          float64   `json:"amount" db:"amount" tag:"Monto dinerario" validate:"required;update;sum" validateType:"porcentaje;menor=40.00;mayor=50.00"`
+	Credits         int64     `json:"credits" db:"credits" tag:"Créditos" validate:"required" validateType:"negativo;min=-40;max=5"`
+	Atcreate        time.Time `json:"atcreate" db:"atcreate" tag:"fecha de registro" validate:"autoCreateTime"`
 }
 */
 
@@ -194,7 +195,7 @@ func parseColumns(columns []map[string]any, table string) []string {
 	for _, column := range columns {
 		var tagColumn string
 		tagColumn += toPascalCase(column["column_name"].(string))
-		tagColumn += fmt.Sprintf(` %s %s json:"%s" db:"%s" tag:"%s"  validate:"required"`, dataTypeCollection[column["udt_name"].(string)], backticks, column["column_name"], column["column_name"], column["column_name"])
+		tagColumn += fmt.Sprintf(` %s %s json:"%s" db:"%s" tag:"%s"  validate:"%s"`, dataTypeCollection[column["udt_name"].(string)], backticks, column["column_name"], column["column_name"], column["column_name"], columnValidate(column))
 
 		var validateType []string
 		if column["udt_name"] == "varchar" || column["udt_name"] == "char" || column["udt_name"] == "text" {
@@ -224,6 +225,19 @@ func parseColumns(columns []map[string]any, table string) []string {
 	return structTable
 }
 
+// columnValidate devuelve la etiqueta validate de la columna, las fechas con DEFAULT now() usan autoCreateTime en lugar de required
+func columnValidate(column map[string]any) string {
+	switch column["udt_name"] {
+	case "timestamp", "timestamptz", "date":
+		def, _ := column["column_default"].(string)
+		def = strings.ToLower(def)
+		if strings.Contains(def, "now()") || strings.Contains(def, "current_timestamp") || strings.Contains(def, "current_date") {
+			return "autoCreateTime"
+		}
+	}
+	return "required"
+}
+
 func stringSchema(structTable []string, table string, nameSchema string) string {
 
 	code_struct := "package tables\n"
@@ -0,0 +1,46 @@
+package migrator
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+/*
+Interfaces de ciclo de vida, las implementan opcionalmente las entidades o los esquemas.
+
+Los hooks Before se ejecutan en SqlExecSingles.Insert/Update/Delete antes de validar y generar las sentencias,
+si retornan error la operación se cancela. Para modificar los datos la entidad debe enviarse como puntero.
+Los hooks After se ejecutan en ExecTransaction después del commit; las variantes Tx se ejecutan dentro de la
+transacción, antes del commit, y si retornan error la transacción se revierte.
+Upsert y BulkInsert ejecutan los hooks de Insert, Restore y HardDelete los de Update y Delete.
+*/
+type (
+	BeforeInsert interface {
+		BeforeInsert(ctx context.Context) error
+	}
+	AfterInsert interface {
+		AfterInsert(ctx context.Context) error
+	}
+	AfterInsertTx interface {
+		AfterInsertTx(ctx context.Context, tx pgx.Tx) error
+	}
+	BeforeUpdate interface {
+		BeforeUpdate(ctx context.Context) error
+	}
+	AfterUpdate interface {
+		AfterUpdate(ctx context.Context) error
+	}
+	AfterUpdateTx interface {
+		AfterUpdateTx(ctx context.Context, tx pgx.Tx) error
+	}
+	BeforeDelete interface {
+		BeforeDelete(ctx context.Context) error
+	}
+	AfterDelete interface {
+		AfterDelete(ctx context.Context) error
+	}
+	AfterDeleteTx interface {
+		AfterDeleteTx(ctx context.Context, tx pgx.Tx) error
+	}
+)
@@ -0,0 +1,93 @@
+package migrator
+
+import "fmt"
+
+type RelationType uint8
+
+const (
+	HasOne RelationType = iota + 1
+	HasMany
+	BelongsTo
+	ManyToMany
+)
+
+/*
+Relation describe la relación entre la tabla de un Schema y otra tabla.
+
+El significado de ForeignKey y References depende del tipo de relación:
+
+  - HasOne / HasMany: ForeignKey es la columna de la tabla relacionada que apunta al dueño,
+    References es la columna del dueño a la que apunta (por defecto "id").
+  - BelongsTo: ForeignKey es la columna del dueño que apunta a la tabla relacionada,
+    References es la columna de la tabla relacionada (por defecto "id").
+  - ManyToMany: References es la columna del dueño (por defecto "id") y ForeignKey la columna de la tabla
+    relacionada (por defecto "id"); JoinTable es la tabla intermedia, JoinForeignKey su columna que apunta al dueño
+    y JoinReferences su columna que apunta a la tabla relacionada.
+
+Model es opcional, cuando se establece Table se toma de su esquema si esta vacío y Preload excluye los registros
+de la tabla relacionada eliminados lógicamente (campo `validate:"softDelete"`) salvo que la consulta use WithDeleted.
+*/
+type Relation struct {
+	Name           string       //Nombre del campo del struct destino donde se cargaran los registros relacionados
+	Type           RelationType //Tipo de relación
+	Table          string       //Tabla relacionada
+	Model          Schema       //Esquema de la tabla relacionada (opcional)
+	ForeignKey     string
+	References     string
+	JoinTable      string
+	JoinForeignKey string
+	JoinReferences string
+}
+
+// Relations es implementada opcionalmente por un Schema que declara sus relaciones con otras tablas.
+type Relations interface {
+	Relations() []Relation
+}
+
+/*
+FindRelation busca la relación name declarada por el Schema y completa los valores por defecto de sus columnas.
+
+	Parámetros
+		* s {Schema}: esquema que declara las relaciones mediante la interfaz Relations
+		* name {string}: nombre de la relación (nombre del campo en el struct destino)
+	Return
+		- (Relation) relación encontrada
+		- (error) si el esquema no declara relaciones o la relación no existe o esta incompleta
+*/
+func FindRelation(s Schema, name string) (Relation, error) {
+	declared, ok := s.(Relations)
+	if !ok {
+		return Relation{}, fmt.Errorf("el esquema de la tabla %s no declara relaciones", s.Table().Name())
+	}
+	for _, rel := range declared.Relations() {
+		if rel.Name != name {
+			continue
+		}
+		if rel.Table == "" && rel.Model != nil {
+			rel.Table = rel.Model.Table().Name()
+		}
+		if rel.Table == "" {
+			return Relation{}, fmt.Errorf("la relación %s no tiene tabla relacionada", name)
+		}
+		if rel.References == "" {
+			rel.References = "id"
+		}
+		switch rel.Type {
+		case HasOne, HasMany, BelongsTo:
+			if rel.ForeignKey == "" {
+				return Relation{}, fmt.Errorf("la relación %s no tiene llave foránea", name)
+			}
+		case ManyToMany:
+			if rel.ForeignKey == "" {
+				rel.ForeignKey = "id"
+			}
+			if rel.JoinTable == "" || rel.JoinForeignKey == "" || rel.JoinReferences == "" {
+				return Relation{}, fmt.Errorf("la relación %s no tiene tabla intermedia completa", name)
+			}
+		default:
+			return Relation{}, fmt.Errorf("la relación %s no tiene un tipo valido", name)
+		}
+		return rel, nil
+	}
+	return Relation{}, fmt.Errorf("la relación %s no existe en la tabla %s", name, s.Table().Name())
+}
@@ -1,11 +1,16 @@
 package migrator
 
 import (
+	"errors"
+	"maps"
 	"reflect"
 	"regexp"
-	"strconv"
+	"slices"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type Entity interface {
@@ -34,6 +39,12 @@ const (
 	String DataType = "string"
 	Time   DataType = "time.Time"
 	Bytes  DataType = "bytes"
+	UUID   DataType = "uuid.UUID"
+)
+
+var (
+	timeType = reflect.TypeFor[time.Time]()
+	uuidType = reflect.TypeFor[uuid.UUID]()
 )
 
 type ArithmeticOperations uint
@@ -52,16 +63,27 @@ Las etiquetas de structure o también llamado etiquetas de campo estos metadatos
 type Fields struct {
 	Name                 string   //Nombre del campo
 	NameOriginal         string   //Nombre del campo original
+	JSON                 string   //Nombre del campo en la etiqueta json, si no tiene se usa el nombre original
 	Description          string   //Descripción del campo
 	Type                 DataType //A bajo nivel es un string donde se especifica de que tipo sera el campo
 	ArithmeticOperations ArithmeticOperations
-	Required             bool        //Si el valor para inserción de este campo es requerido o no
-	PrimaryKey           bool        //Si el campo es primary key entonces es obligatorio este campo para insert,update y delete
-	Where                bool        //El campo puede ser utilizado para filtrar al utilizar el update y delete
-	Update               bool        //El campo puede ser modificado
-	Default              interface{} //Valor por defecto que se tomara si no se le valor al campo, el tipo del valor debe de ser igual al Type del campo
-	Empty                bool        //El campo aceptara valor vació si se realiza la actualización
-	ValidateType         interface{} //Los datos serán validados mas a fondo mediante esta opción para eso se le debe de asignar los siguientes typo de struct: TypeStrings, TypeFloat64, TypeUint64 yTypeInt64
+	Required             bool         //Si el valor para inserción de este campo es requerido o no
+	PrimaryKey           bool         //Si el campo es primary key entonces es obligatorio este campo para insert,update y delete
+	Where                bool         //El campo puede ser utilizado para filtrar al utilizar el update y delete
+	Update               bool         //El campo puede ser modificado
+	Default              interface{}  //Valor por defecto que se tomara si no se le valor al campo, el tipo del valor debe de ser igual al Type del campo
+	Empty                bool         //El campo aceptara valor vació si se realiza la actualización
+	ValidateType         interface{}  //Los datos serán validados mas a fondo mediante esta opción para eso se le debe de asignar los siguientes typo de struct: TypeStrings, TypeFloat64, TypeUint64, TypeInt64, TypeDate, TypeBytes y TypeUUID
+	Version              bool         //El campo guarda la versión del registro (bloqueo optimista), se compara y se incrementa en cada actualización
+	SoftDelete           bool         //El campo guarda la fecha de eliminación lógica, Delete lo establece en lugar de eliminar el registro
+	AutoCreateTime       bool         //El campo recibe la fecha actual al insertar si no tiene valor
+	AutoUpdateTime       bool         //El campo recibe la fecha actual al insertar y en cada actualización
+	TimeFormat           TimeFormat   //Formato de la fecha de los campos autoCreateTime, autoUpdateTime y softDelete
+	Validators           []CustomRule //Reglas personalizadas registradas con RegisterValidator (custom=nombre o fn=nombre(params))
+	CrossRules           []CrossRule  //Reglas que relacionan el campo con otros campos (required_if, gtfield, etc.)
+
+	owner reflect.Type // struct que genero el esquema
+	index []int        // índice del campo en owner
 }
 
 type TypeStrings struct {
@@ -76,6 +98,17 @@ type TypeStrings struct {
 }
 
 type TypeDate struct {
+	Min time.Time //Fecha mínima que aceptara el campo (min=2006-01-02 o RFC3339)
+	Max time.Time //Fecha máxima que aceptara el campo (max=2006-01-02 o RFC3339)
+}
+
+type TypeBytes struct {
+	Min int //Cuantos bytes como mínimo debe de tener el valor del campo
+	Max int //Cuantos bytes como máximo debe de tener el valor del campo
+}
+
+type TypeUUID struct {
+	Version int //Versión que debe tener el uuid (version=4), 0 acepta cualquier versión
 }
 
 type TypeBoolean struct {
@@ -134,140 +167,253 @@ const (
 type EntityUpdate struct {
 	Entity
 	Conditions []Where
+	Set        []string //Campos que se actualizan aunque su valor sea cero (0, false, "") o nil (NULL)
+}
+
+/*
+UpsertOptions define como se resuelve el conflicto de un INSERT ... ON CONFLICT.
+
+Si no se indica Constraint ni Columns el conflicto se detecta por los campos `primaryKey` del esquema.
+Columns debe contener columnas del esquema y Constraint se envía como identificador entre comillas (respeta mayúsculas).
+*/
+type UpsertOptions struct {
+	Constraint string   //Nombre de la restricción única (ON CONFLICT ON CONSTRAINT)
+	Columns    []string //Columnas que forman la restricción única
+	DoNothing  bool     //Ignora el registro en conflicto en lugar de actualizarlo
 }
 
+/*
+SoftDeleteField busca el campo marcado con `validate:"softDelete"` en el esquema.
+
+	Parámetros
+		* fields {[]Fields}: campos generados por GenerateSchema
+	Return
+		- (Fields) campo de eliminación lógica
+		- (bool) false si la tabla no usa eliminación lógica
+*/
+func SoftDeleteField(fields []Fields) (Fields, bool) {
+	for _, f := range fields {
+		if f.SoftDelete {
+			return f, true
+		}
+	}
+	return Fields{}, false
+}
+
+/*
+GenerateSchema genera los campos del esquema a partir de las etiquetas del struct para la acción indicada.
+
+Las reglas con errores de sintaxis se ignoran y se registran una vez por struct con slog,
+para detenerse ante estos errores use ParseSchema o MustCheckSchemas al iniciar la aplicación.
+*/
 func GenerateSchema(data any, action Actions) []Fields {
-	t := reflect.TypeOf(data)
-	v := reflect.ValueOf(data)
+	schema, err := ParseSchema(data, action)
+	if err != nil {
+		reportSchemaError(reflect.TypeOf(data), err)
+	}
+	return schema
+}
+
+/*
+ParseSchema genera los campos del esquema y valida las etiquetas validate y validateType.
+
+	Parámetros
+		* data {any}: entidad (struct) con las etiquetas
+		* action {Actions}: INSERT, UPDATE o DELETE, determina los campos que forman el esquema
+	Return
+		- ([]Fields) campos del esquema, las reglas con error se ignoran
+		- (error) TagError de cada regla desconocida o con parámetro inválido unidos con errors.Join
+
+El esquema se compila una sola vez por tipo y acción, en las siguientes llamadas solo se toma de data el valor
+de los campos `default`. Cada llamada retorna una copia de []Fields que se puede modificar sin afectar al cache.
+*/
+func ParseSchema(data any, action Actions) ([]Fields, error) {
+	v := reflect.Indirect(reflect.ValueOf(data))
+	key := schemaKey{t: v.Type(), action: action}
+	cached, ok := schemaCache.Load(key)
+	if !ok {
+		cached, _ = schemaCache.LoadOrStore(key, compileSchema(v.Type(), action))
+	}
+	compiled := cached.(*compiledSchema)
+	return compiled.bind(v), compiled.err
+}
+
+// schemaKey identifica un esquema compilado
+type schemaKey struct {
+	t      reflect.Type
+	action Actions
+}
+
+// compiledSchema es el esquema de un tipo y acción, defaults son las posiciones de los campos cuyo Default depende del valor de la entidad
+type compiledSchema struct {
+	fields   []Fields
+	defaults []int
+	err      error
+}
+
+// schemaCache guarda los esquemas compilados por tipo y acción
+var schemaCache sync.Map
+
+// bind retorna una copia de los campos del esquema con el valor por defecto tomado de la entidad v
+func (c *compiledSchema) bind(v reflect.Value) []Fields {
+	fields := make([]Fields, len(c.fields))
+	for i, f := range c.fields {
+		fields[i] = f.clone()
+	}
+	for _, i := range c.defaults {
+		fields[i].Default = v.FieldByIndex(fields[i].index).Interface()
+	}
+	return fields
+}
+
+// clone copia el campo junto con sus slices (reglas e índice) para que modificar la copia no afecte al cache
+func (f Fields) clone() Fields {
+	f.index = slices.Clone(f.index)
+	f.CrossRules = slices.Clone(f.CrossRules)
+	if f.Validators != nil {
+		validators := make([]CustomRule, len(f.Validators))
+		for i, v := range f.Validators {
+			v.Params = slices.Clone(v.Params)
+			validators[i] = v
+		}
+		f.Validators = validators
+	}
+	return f
+}
+
+// compileSchema genera los campos del esquema del tipo t para la acción indicada
+func compileSchema(t reflect.Type, action Actions) *compiledSchema {
+	compiled := &compiledSchema{}
 	var schema []Fields
+	var errs []error
+	tagErrors := func(field reflect.StructField, tag string, ruleErrs map[string]string) {
+		for _, rule := range slices.Sorted(maps.Keys(ruleErrs)) {
+			errs = append(errs, &TagError{Struct: t.Name(), Field: field.Name, Tag: tag, Rule: rule, Err: ruleErrs[rule]})
+		}
+	}
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
-		value := v.Field(i)
 
 		var structSchema Fields
+		structSchema.owner = t
+		structSchema.index = field.Index
 		structSchema.Name = strings.ToLower(field.Name)
 		structSchema.NameOriginal = field.Name
 		structSchema.Description = field.Tag.Get("tag")
-
-		if structSchema.Name == "atcreate" {
-			structSchema.Default = time.Now().UTC()
+		structSchema.JSON = jsonName(field)
+		structSchema.Type = dataType(field.Type)
+
+		rules := parseTag(field.Tag.Get("validate"), ";,")
+		tagErrors(field, "validate", checkValidateTag(rules))
+		crossRules, crossErrs := parseCrossRules(t, rules)
+		tagErrors(field, "validate", crossErrs)
+		validateType, validators, typeErrs := parseValidateType(structSchema.Type, parseTag(field.Tag.Get("validateType"), ";"))
+		tagErrors(field, "validateType", typeErrs)
+
+		structSchema.PrimaryKey = rules.has("primaryKey")
+		structSchema.Where = rules.has("where")
+		structSchema.SoftDelete = rules.has("softDelete")
+
+		createParam, autoCreate := rules.param("autoCreateTime")
+		updateParam, autoUpdate := rules.param("autoUpdateTime")
+		if !autoCreate && structSchema.Name == "atcreate" && structSchema.Type == Time {
+			autoCreate = true
+			warnLegacyAtcreate(t)
 		}
-
-		validateTag := field.Tag.Get("validate")
-		structSchema.PrimaryKey = strings.Contains(validateTag, "primaryKey")
-		structSchema.Where = strings.Contains(validateTag, "where")
+		structSchema.AutoCreateTime = autoCreate
+		structSchema.AutoUpdateTime = autoUpdate
+		structSchema.TimeFormat = timeFormat(field.Type, createParam+updateParam)
 
 		if action == DELETE {
-			if !structSchema.PrimaryKey && !structSchema.Where {
+			if !structSchema.PrimaryKey && !structSchema.Where && !structSchema.SoftDelete {
 				continue
 			}
 		}
 
-		structSchema.Update = strings.Contains(validateTag, "update")
+		structSchema.Update = rules.has("update")
+		structSchema.Version = rules.has("version")
 		if action == UPDATE {
-			if !structSchema.Update && !structSchema.PrimaryKey && !structSchema.Where {
+			if !structSchema.Update && !structSchema.PrimaryKey && !structSchema.Where && !structSchema.Version && !structSchema.SoftDelete && !structSchema.AutoUpdateTime {
 				continue
 			}
 
-			if strings.Contains(validateTag, "sum") {
+			if rules.has("sum") {
 				structSchema.ArithmeticOperations = Sum
-			} else if strings.Contains(validateTag, "subtraction") {
+			} else if rules.has("subtraction") {
 				structSchema.ArithmeticOperations = Subtraction
-			} else if strings.Contains(validateTag, "multiply") {
+			} else if rules.has("multiply") {
 				structSchema.ArithmeticOperations = Multiply
-			} else if strings.Contains(validateTag, "divide") {
+			} else if rules.has("divide") {
 				structSchema.ArithmeticOperations = Divide
 			}
 		}
 
-		structSchema.Required = strings.Contains(validateTag, "required")
+		structSchema.Required = rules.has("required")
+		structSchema.CrossRules = crossRules
 
-		structSchema.Type = DataType(field.Type.String())
-		// fmt.Println(field.Type, ":", field.Name)
-		//fmt.Println("DataType(field.Type.Name()):", DataType(field.Type.String()))
-		if strings.Contains(validateTag, "default") {
-			structSchema.Default = value.Interface()
-		}
-		validateTypeTag := field.Tag.Get("validateType")
-		rules := strings.Split(validateTypeTag, ";")
-		switch value.Interface().(type) {
-		case string, *string:
-			schemaType := TypeStrings{}
-			for _, rule := range rules {
-				if after, ok := strings.CutPrefix(rule, "min="); ok {
-					min, _ := strconv.Atoi(after)
-					schemaType.Min = min
-				}
-				if after, ok := strings.CutPrefix(rule, "max="); ok {
-					max, _ := strconv.Atoi(after)
-					schemaType.Max = max
-				}
-				if after, ok := strings.CutPrefix(rule, "case="); ok {
-					switch after {
-					case "lowercase":
-						schemaType.LowerCase = true
-					case "uppercase":
-						schemaType.UpperCase = true
-					}
-				}
-				if strings.Contains(rule, "encrypt") {
-					schemaType.Encriptar = true
-				}
-				if strings.Contains(rule, "cipher") {
-					schemaType.Cifrar = true
-				}
-				if after, ok := strings.CutPrefix(rule, "expr="); ok {
-					schemaType.Expr = regexp.MustCompile(after)
-
-				}
-			}
-			structSchema.ValidateType = schemaType
-		case float32, float64, *float32, *float64:
-			schemaType := TypeFloat64{}
-			for _, rule := range rules {
-				if after, ok := strings.CutPrefix(rule, "menor="); ok {
-					menor, _ := strconv.ParseFloat(after, 64)
-					schemaType.Menor = menor
-				} else if after0, ok0 := strings.CutPrefix(rule, "mayor="); ok0 {
-					mayor, _ := strconv.ParseFloat(after0, 64)
-					schemaType.Mayor = mayor
-				} else if strings.Contains(rule, "negative") {
-					schemaType.Negativo = true
-				} else if strings.Contains(rule, "porcentaje") {
-					schemaType.Porcentaje = true
-				}
-			}
-			structSchema.ValidateType = schemaType
-		case int, int8, int16, int32, int64, *int, *int8, *int16, *int32, *int64:
-			schemaType := TypeInt64{}
-			for _, rule := range rules {
-				if after, ok := strings.CutPrefix(rule, "min="); ok {
-					min, _ := strconv.Atoi(after)
-					schemaType.Min = int64(min)
-				} else if after0, ok0 := strings.CutPrefix(rule, "max="); ok0 {
-					max, _ := strconv.Atoi(after0)
-					schemaType.Max = int64(max)
-				} else if strings.Contains(rule, "negativo") {
-					schemaType.Negativo = true
-				}
-			}
-			structSchema.ValidateType = schemaType
-		case uint, uint8, uint16, uint32, uint64, *uint, *uint8, *uint16, *uint32, *uint64:
-			schemaType := TypeUint64{}
-			for _, rule := range rules {
-				if after, ok := strings.CutPrefix(rule, "max="); ok {
-					max, _ := strconv.Atoi(after)
-					schemaType.Max = uint64(max)
-				}
-			}
-			structSchema.ValidateType = schemaType
-		case time.Time, *time.Time:
-			schemaType := TypeDate{}
-			structSchema.ValidateType = schemaType
+		if rules.has("default") {
+			compiled.defaults = append(compiled.defaults, len(schema))
 		}
+		structSchema.ValidateType = validateType
+		structSchema.Validators = validators
 
 		schema = append(schema, structSchema)
 	}
 
-	return schema
+	compiled.fields = slices.Clip(schema)
+	compiled.err = errors.Join(errs...)
+	return compiled
+}
+
+// FieldValue retorna el valor del campo en la entidad v (struct, no puntero) usando el índice precalculado del esquema
+func (f Fields) FieldValue(v reflect.Value) reflect.Value {
+	if f.owner == v.Type() {
+		return v.FieldByIndex(f.index)
+	}
+	return v.FieldByName(f.NameOriginal)
+}
+
+// jsonName retorna el nombre del campo según su etiqueta json
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// indirectType retorna el tipo apuntado si t es un puntero
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
+
+// dataType clasifica el tipo del campo (valor o puntero) en los DataType que sabe validar, los demás tipos conservan su nombre
+func dataType(t reflect.Type) DataType {
+	t = indirectType(t)
+	switch t {
+	case timeType:
+		return Time
+	case uuidType:
+		return UUID
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return Bool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Int
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Uint
+	case reflect.Float32, reflect.Float64:
+		return Float
+	case reflect.String:
+		return String
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Bytes
+		}
+	}
+	return DataType(t.String())
 }
@@ -0,0 +1,104 @@
+package migrator_test
+
+import (
+	"testing"
+
+	"github.com/deybin/pgorm/migrator"
+	"github.com/google/uuid"
+)
+
+// schema es el esquema de las entidades de prueba, cada test declara su propia entidad
+type schema[T migrator.Entity] struct {
+	table T
+}
+
+func (s schema[T]) Table() migrator.Entity {
+	return s.table
+}
+
+func (s schema[T]) ParseInsert() []migrator.Fields {
+	return migrator.GenerateSchema(s.table, migrator.INSERT)
+}
+
+func (s schema[T]) ParseUpdate() []migrator.Fields {
+	return migrator.GenerateSchema(s.table, migrator.UPDATE)
+}
+
+func (s schema[T]) ParseDelete() []migrator.Fields {
+	return migrator.GenerateSchema(s.table, migrator.DELETE)
+}
+
+// rulesOf devuelve la regla que fallo en cada campo (por su nombre json)
+func rulesOf(errs migrator.ValidationErrors) map[string]string {
+	rules := map[string]string{}
+	for _, v := range errs {
+		rules[v.JSON] = v.Rule
+	}
+	return rules
+}
+
+type cachedItem struct {
+	Id    string `json:"id" validate:"primaryKey;required;default"`
+	Price float64
+}
+
+func (e cachedItem) Name() string      { return "cached_items" }
+func (e cachedItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e cachedItem) Values() []any     { return migrator.EntityValues(e) }
+
+func TestSchemaCache(t *testing.T) {
+	first, second := schema[cachedItem]{}.ParseInsert(), schema[cachedItem]{}.ParseInsert()
+	if len(first) != 2 || first[0].Name != "id" || !first[0].PrimaryKey || first[1].Name != "price" {
+		t.Fatalf("esquema inesperado: %+v", first)
+	}
+
+	// el valor por defecto del campo id se toma de la entidad en cada llamada
+	withId := migrator.GenerateSchema(cachedItem{Id: uuid.NewString()}, migrator.INSERT)
+	if first[0].Default != "" || withId[0].Default == "" || second[0].Default != "" {
+		t.Errorf("se esperaba el valor por defecto de cada entidad: %v %v %v", first[0].Default, withId[0].Default, second[0].Default)
+	}
+}
+
+type plainItem struct {
+	Id    string `validate:"primaryKey;required"`
+	Price float64
+}
+
+func (e plainItem) Name() string      { return "plain_items" }
+func (e plainItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e plainItem) Values() []any     { return migrator.EntityValues(e) }
+
+func TestSchemaCache_ReturnsCopy(t *testing.T) {
+	fields := schema[plainItem]{}.ParseInsert()
+	fields[0].Name = "changed"
+	fields[0].PrimaryKey = false
+	if next := (schema[plainItem]{}).ParseInsert(); next[0].Name != "id" || !next[0].PrimaryKey {
+		t.Errorf("modificar el esquema retornado no debe afectar al cache: %+v", next[0])
+	}
+}
+
+type rulesItem struct {
+	Id    string `validate:"primaryKey;required"`
+	Start int64
+	End   int64 `validate:"gtfield=Start" validateType:"fn=between(1,5)"`
+}
+
+func (e rulesItem) Name() string      { return "rules_items" }
+func (e rulesItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e rulesItem) Values() []any     { return migrator.EntityValues(e) }
+
+func TestSchemaCache_ReturnsDeepCopy(t *testing.T) {
+	fields := schema[rulesItem]{}.ParseInsert()
+	end := fields[2]
+	if len(end.Validators) != 1 || len(end.Validators[0].Params) != 2 || len(end.CrossRules) != 1 {
+		t.Fatalf("reglas inesperadas: %+v", end)
+	}
+	end.Validators[0].Params[0] = "changed"
+	end.CrossRules[0].Field = "Id"
+	fields[2].Validators = append(fields[2].Validators[:1], migrator.CustomRule{Name: "extra"})
+
+	next := schema[rulesItem]{}.ParseInsert()[2]
+	if len(next.Validators) != 1 || next.Validators[0].Params[0] != "1" || next.CrossRules[0].Field != "Start" {
+		t.Errorf("modificar las reglas retornadas no debe afectar al cache: %+v", next)
+	}
+}
@@ -0,0 +1,371 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+Gramática de las etiquetas validate y validateType:
+
+	etiqueta = regla { separador regla }
+	regla    = nombre [ "=" parámetro ]
+
+En validate las reglas se separan con ";" o ",", en validateType solo con ";" (los parámetros de fn=nombre(a,b) usan ",").
+Un separador precedido de "\" es parte del parámetro, en el código fuente se escribe `validateType:"expr=^[a-z]\\;[0-9]$"`
+porque el valor de la etiqueta es un string con comillas; cualquier otra secuencia con "\" se conserva tal cual para las
+expresiones regulares. Las reglas desconocidas y los parámetros inválidos se reportan con TagError.
+*/
+
+// TagError es un error de sintaxis en las etiquetas validate o validateType de un campo
+type TagError struct {
+	Struct string // nombre del struct
+	Field  string // nombre del campo
+	Tag    string // validate o validateType
+	Rule   string // regla con el error
+	Err    string
+}
+
+func (e *TagError) Error() string {
+	return fmt.Sprintf("%s.%s: etiqueta %s, regla %q: %s", e.Struct, e.Field, e.Tag, e.Rule, e.Err)
+}
+
+type tagRule struct {
+	name  string
+	param string
+}
+
+type tagRules []tagRule
+
+func (r tagRules) has(name string) bool {
+	_, ok := r.param(name)
+	return ok
+}
+
+func (r tagRules) param(name string) (string, bool) {
+	for _, rule := range r {
+		if rule.name == name {
+			return rule.param, true
+		}
+	}
+	return "", false
+}
+
+// parseTag separa la etiqueta en reglas, separators son los caracteres que separan las reglas y se pueden escapar con "\"
+func parseTag(tag string, separators string) tagRules {
+	var rules tagRules
+	var current strings.Builder
+	flush := func() {
+		rule := strings.TrimSpace(current.String())
+		current.Reset()
+		if rule == "" {
+			return
+		}
+		name, param, _ := strings.Cut(rule, "=")
+		rules = append(rules, tagRule{name: strings.TrimSpace(name), param: strings.TrimSpace(param)})
+	}
+	for i := 0; i < len(tag); i++ {
+		c := tag[i]
+		if c == '\\' && i+1 < len(tag) && strings.IndexByte(separators, tag[i+1]) >= 0 {
+			current.WriteByte(tag[i+1])
+			i++
+			continue
+		}
+		if strings.IndexByte(separators, c) >= 0 {
+			flush()
+			continue
+		}
+		current.WriteByte(c)
+	}
+	flush()
+	return rules
+}
+
+// validateRules son las reglas de la etiqueta validate, el valor indica si la regla recibe parámetro
+var validateRules = map[string]bool{
+	"primaryKey": false, "required": false, "where": false, "update": false, "version": false, "softDelete": false,
+	"sum": false, "subtraction": false, "multiply": false, "divide": false,
+	"default": false, "autoCreateTime": true, "autoUpdateTime": true,
+}
+
+// checkValidateTag verifica que las reglas de validate existan y tengan parámetros válidos
+func checkValidateTag(rules tagRules) map[string]string {
+	errs := make(map[string]string)
+	for _, rule := range rules {
+		if _, cross := crossRuleNames[rule.name]; cross {
+			continue // las valida parseCrossRules
+		}
+		acceptsParam, ok := validateRules[rule.name]
+		switch {
+		case !ok:
+			errs[rule.name] = "regla desconocida"
+		case !acceptsParam && rule.param != "":
+			errs[rule.name] = "no recibe parámetro"
+		case rule.name == "autoCreateTime" || rule.name == "autoUpdateTime":
+			if rule.param != "" && rule.param != "milli" && rule.param != "nano" {
+				errs[rule.name] = fmt.Sprintf("formato %q no valido (milli o nano)", rule.param)
+			}
+		}
+	}
+	return errs
+}
+
+/*
+parseValidateType interpreta las reglas de validateType según el tipo del campo.
+
+	Return
+		- (any) reglas del tipo: TypeStrings, TypeFloat64, TypeInt64, TypeUint64, TypeDate, TypeBytes, TypeUUID o TypeBoolean
+		- ([]CustomRule) reglas personalizadas (custom y fn)
+		- (map[string]string) errores por regla, las reglas con error se ignoran
+*/
+func parseValidateType(dt DataType, rules tagRules) (any, []CustomRule, map[string]string) {
+	errs := make(map[string]string)
+	var custom []CustomRule
+	var validateType any
+	switch dt {
+	case String:
+		validateType = TypeStrings{}
+	case Float:
+		validateType = TypeFloat64{}
+	case Int:
+		validateType = TypeInt64{}
+	case Uint:
+		validateType = TypeUint64{}
+	case Time:
+		validateType = TypeDate{}
+	case Bytes:
+		validateType = TypeBytes{}
+	case UUID:
+		validateType = TypeUUID{}
+	case Bool:
+		validateType = TypeBoolean{}
+	}
+
+	for _, rule := range rules {
+		if rule.name == "custom" || rule.name == "fn" {
+			c, err := parseCustomRule(rule.name, rule.param)
+			if err != nil {
+				errs[rule.name] = err.Error()
+				continue
+			}
+			custom = append(custom, c)
+			continue
+		}
+
+		var err error
+		switch schemaType := validateType.(type) {
+		case TypeStrings:
+			err = stringRule(&schemaType, rule)
+			validateType = schemaType
+		case TypeFloat64:
+			err = floatRule(&schemaType, rule)
+			validateType = schemaType
+		case TypeInt64:
+			err = intRule(&schemaType, rule)
+			validateType = schemaType
+		case TypeUint64:
+			err = uintRule(&schemaType, rule)
+			validateType = schemaType
+		case TypeDate:
+			err = dateRule(&schemaType, rule)
+			validateType = schemaType
+		case TypeBytes:
+			err = bytesRule(&schemaType, rule)
+			validateType = schemaType
+		case TypeUUID:
+			err = uuidRule(&schemaType, rule)
+			validateType = schemaType
+		default:
+			err = errUnknownRule
+		}
+		if err != nil {
+			errs[rule.name] = err.Error()
+		}
+	}
+	return validateType, custom, errs
+}
+
+var (
+	errUnknownRule = errors.New("regla desconocida para el tipo del campo")
+	errNoParam     = errors.New("no recibe parámetro")
+)
+
+func noParam(rule tagRule) error {
+	if rule.param != "" {
+		return errNoParam
+	}
+	return nil
+}
+
+func intParam(rule tagRule) (int, error) {
+	value, err := strconv.Atoi(rule.param)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("el parámetro %q debe ser un entero positivo", rule.param)
+	}
+	return value, nil
+}
+
+func stringRule(s *TypeStrings, rule tagRule) (err error) {
+	switch rule.name {
+	case "min":
+		s.Min, err = intParam(rule)
+	case "max":
+		s.Max, err = intParam(rule)
+	case "case":
+		switch rule.param {
+		case "lowercase":
+			s.LowerCase = true
+		case "uppercase":
+			s.UpperCase = true
+		default:
+			err = fmt.Errorf("el parámetro %q debe ser lowercase o uppercase", rule.param)
+		}
+	case "encrypt":
+		s.Encriptar, err = true, noParam(rule)
+	case "cipher":
+		s.Cifrar, err = true, noParam(rule)
+	case "expr":
+		s.Expr, err = regexp.Compile(rule.param)
+	default:
+		err = errUnknownRule
+	}
+	return err
+}
+
+func floatRule(s *TypeFloat64, rule tagRule) (err error) {
+	switch rule.name {
+	case "menor":
+		s.Menor, err = strconv.ParseFloat(rule.param, 64)
+	case "mayor":
+		s.Mayor, err = strconv.ParseFloat(rule.param, 64)
+	case "negative", "negativo":
+		s.Negativo, err = true, noParam(rule)
+	case "porcentaje":
+		s.Porcentaje, err = true, noParam(rule)
+	default:
+		return errUnknownRule
+	}
+	if err != nil && !errors.Is(err, errNoParam) {
+		err = fmt.Errorf("el parámetro %q debe ser un número", rule.param)
+	}
+	return err
+}
+
+func intRule(s *TypeInt64, rule tagRule) (err error) {
+	switch rule.name {
+	case "min":
+		s.Min, err = strconv.ParseInt(rule.param, 10, 64)
+	case "max":
+		s.Max, err = strconv.ParseInt(rule.param, 10, 64)
+	case "negativo", "negative":
+		s.Negativo, err = true, noParam(rule)
+	default:
+		return errUnknownRule
+	}
+	if err != nil && !errors.Is(err, errNoParam) {
+		err = fmt.Errorf("el parámetro %q debe ser un entero", rule.param)
+	}
+	return err
+}
+
+func uintRule(s *TypeUint64, rule tagRule) (err error) {
+	if rule.name != "max" {
+		return errUnknownRule
+	}
+	if s.Max, err = strconv.ParseUint(rule.param, 10, 64); err != nil {
+		err = fmt.Errorf("el parámetro %q debe ser un entero positivo", rule.param)
+	}
+	return err
+}
+
+func dateRule(s *TypeDate, rule tagRule) (err error) {
+	var date time.Time
+	switch rule.name {
+	case "min", "max":
+		if date, err = parseDate(rule.param); err != nil {
+			return fmt.Errorf("el parámetro %q debe ser una fecha 2006-01-02 o RFC3339", rule.param)
+		}
+	default:
+		return errUnknownRule
+	}
+	if rule.name == "min" {
+		s.Min = date
+	} else {
+		s.Max = date
+	}
+	return nil
+}
+
+func bytesRule(s *TypeBytes, rule tagRule) (err error) {
+	switch rule.name {
+	case "min":
+		s.Min, err = intParam(rule)
+	case "max":
+		s.Max, err = intParam(rule)
+	default:
+		err = errUnknownRule
+	}
+	return err
+}
+
+func uuidRule(s *TypeUUID, rule tagRule) (err error) {
+	if rule.name != "version" {
+		return errUnknownRule
+	}
+	if s.Version, err = strconv.Atoi(rule.param); err != nil || s.Version < 1 || s.Version > 8 {
+		s.Version = 0
+		return fmt.Errorf("el parámetro %q debe ser una versión entre 1 y 8", rule.param)
+	}
+	return nil
+}
+
+// parseDate interpreta las fechas de las reglas min y max de time.Time (2006-01-02 o RFC3339)
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// reportedSchemas evita repetir en el log los errores de etiquetas de un mismo struct
+var reportedSchemas sync.Map
+
+// reportSchemaError registra una vez por struct los errores de etiquetas encontrados por GenerateSchema
+func reportSchemaError(t reflect.Type, err error) {
+	if _, loaded := reportedSchemas.LoadOrStore(t, true); loaded {
+		return
+	}
+	slog.Error("etiquetas de validación inválidas, las reglas con error se ignoran", "struct", t.String(), "error", err)
+}
+
+/*
+CheckSchemas verifica las etiquetas validate y validateType de las entidades de los esquemas.
+
+	Parámetros
+		* schemas {...Schema}: esquemas de la aplicación
+	Return
+		- (error) todos los TagError encontrados unidos con errors.Join, nil si las etiquetas son válidas
+*/
+func CheckSchemas(schemas ...Schema) error {
+	var errs []error
+	for _, s := range schemas {
+		if _, err := ParseSchema(s.Table(), INSERT); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// MustCheckSchemas es CheckSchemas que termina la aplicación con panic si alguna etiqueta es inválida, se usa al iniciar la aplicación
+func MustCheckSchemas(schemas ...Schema) {
+	if err := CheckSchemas(schemas...); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,38 @@
+package migrator_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/deybin/pgorm/migrator"
+)
+
+func TestParseSchema_TagErrors(t *testing.T) {
+	type invalid struct {
+		Id    string  `validate:"primaryKey;requierd"`
+		Stock int64   `validate:"update" validateType:"max=1O"`
+		Code  string  `validateType:"expr=^[a-z]+\\;[0-9]+$;case=title"`
+		Price float64 `validate:"gtfield=Cost"`
+		Name  string  `validate:"default=nombre"`
+	}
+	fields, err := migrator.ParseSchema(invalid{}, migrator.INSERT)
+	var tagErr *migrator.TagError
+	if !errors.As(err, &tagErr) {
+		t.Fatalf("se esperaba TagError: %v", err)
+	}
+	for _, rule := range []string{`"requierd"`, `"max"`, `"case"`, `"gtfield"`, `"default"`} {
+		if !strings.Contains(err.Error(), rule) {
+			t.Errorf("se esperaba el error de la regla %s: %v", rule, err)
+		}
+	}
+	if expr := fields[2].ValidateType.(migrator.TypeStrings).Expr; expr == nil || !expr.MatchString("abc;123") {
+		t.Errorf("la expresión con ; escapado no se interpreto: %v", expr)
+	}
+}
+
+func TestCheckSchemas(t *testing.T) {
+	if err := migrator.CheckSchemas(schema[errorsItem]{}, schema[scalarItem]{}, schema[contractItem]{}, schema[accountItem]{}); err != nil {
+		t.Errorf("no se esperaba este error: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package migrator
+
+import (
+	"context"
+)
+
+/*
+ValidateInsert valida la entidad con las reglas de inserción del esquema sin generar sentencias,
+permite rechazar los datos en un handler antes de crear la transacción.
+
+Se aplican las mismas reglas que en SqlExecSingles.Insert (incluye reglas personalizadas, entre campos y Validate),
+salvo encrypt y cipher: esos campos se validan pero su valor se retorna sin hash ni cifrado.
+
+	Parámetros
+		* schema {Schema}: esquema de la tabla
+		* entity {Entity}: entidad a validar (valor o puntero)
+	Return
+		- (map[string]any) valores normalizados por columna (trim, mayúsculas, valores por defecto y fechas automáticas)
+		- (error) ValidationErrors con las fallas por campo
+*/
+func ValidateInsert(schema Schema, entity Entity) (map[string]any, error) {
+	return ValidateInsertContext(context.Background(), schema, entity)
+}
+
+// ValidateInsertContext es ValidateInsert con el contexto que reciben las reglas personalizadas y el idioma de los mensajes
+func ValidateInsertContext(ctx context.Context, schema Schema, entity Entity) (map[string]any, error) {
+	return checkInsert(ctx, schema.ParseInsert(), entity, false)
+}
+
+/*
+ValidateUpdate valida los nuevos valores de la entidad con las reglas de actualización del esquema sin generar sentencias,
+encrypt y cipher se validan pero su valor se retorna sin hash ni cifrado.
+
+	Parámetros
+		* schema {Schema}: esquema de la tabla
+		* entity {any}: entidad con los nuevos valores o EntityUpdate (sus campos Set se suman a set)
+		* set {...string}: campos que se actualizan aunque su valor sea cero
+	Return
+		- (map[string]any) valores normalizados por columna, los campos aritméticos usan las llaves ADD_<columna>_<OPERACIÓN>
+		- (error) ValidationErrors con las fallas por campo
+*/
+func ValidateUpdate(schema Schema, entity any, set ...string) (map[string]any, error) {
+	return ValidateUpdateContext(context.Background(), schema, entity, set...)
+}
+
+// ValidateUpdateContext es ValidateUpdate con el contexto que reciben las reglas personalizadas y el idioma de los mensajes
+func ValidateUpdateContext(ctx context.Context, schema Schema, entity any, set ...string) (map[string]any, error) {
+	switch update := entity.(type) {
+	case EntityUpdate:
+		entity, set = update.Entity, append(set, update.Set...)
+	case *EntityUpdate:
+		entity, set = update.Entity, append(set, update.Set...)
+	}
+	return checkUpdate(ctx, schema.ParseUpdate(), entity, set, false)
+}
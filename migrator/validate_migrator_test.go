@@ -0,0 +1,43 @@
+package migrator_test
+
+import (
+	"testing"
+
+	"github.com/deybin/pgorm/migrator"
+)
+
+type accountItem struct {
+	Id        string `validate:"primaryKey;required"`
+	Passwords string `validate:"required" validateType:"encrypt"`
+	Secret    string `validate:"required" validateType:"cipher"`
+	Email     string `validate:"required;update" validateType:"case=lowercase;expr=^[a-zA-Z0-9.]+@[a-z0-9]+(\\.[a-z0-9]+)+$"`
+	Credits   uint64 `validate:"update;sum"`
+}
+
+func (e accountItem) Name() string      { return "account_items" }
+func (e accountItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e accountItem) Values() []any     { return migrator.EntityValues(e) }
+
+func TestValidateInsert(t *testing.T) {
+	entity := accountItem{Id: "1", Passwords: "secreto", Secret: "clave", Email: "JUAN@mail.com"}
+	data, err := migrator.ValidateInsert(schema[accountItem]{}, entity)
+	if err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	if data["passwords"] != "secreto" || data["secret"] != "clave" || data["email"] != "juan@mail.com" {
+		t.Errorf("ValidateInsert no debe encriptar ni cifrar: %v", data)
+	}
+
+	entity.Email = "juan"
+	if _, err := migrator.ValidateInsert(schema[accountItem]{}, entity); err == nil {
+		t.Errorf("se esperaba la falla de la regla expr")
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	update := migrator.EntityUpdate{Entity: accountItem{}, Set: []string{"credits"}}
+	data, err := migrator.ValidateUpdate(schema[accountItem]{}, update)
+	if err != nil || data["ADD_credits_SUMA"] != uint64(0) {
+		t.Errorf("valores inesperados: %v %v", data, err)
+	}
+}
@@ -0,0 +1,114 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+)
+
+/*
+ValidatorFunc es una regla de validación personalizada, retorna error si el valor no es válido.
+
+	Parámetros
+		* ctx {context.Context}: contexto de la validación, la entidad completa se obtiene con EntityContext
+		* value {any}: valor del campo (si es puntero se recibe el valor apuntado)
+		* params {[]string}: parámetros de la regla, por ejemplo `fn=ruc(11)` recibe ["11"]
+*/
+type ValidatorFunc func(ctx context.Context, value any, params []string) error
+
+// CustomRule es una regla personalizada del campo, se declara con `validateType:"custom=ruc"` o `validateType:"fn=ruc(11)"`
+type CustomRule struct {
+	Name   string
+	Params []string
+}
+
+type entityKey struct{}
+
+var (
+	validatorsMu sync.RWMutex
+	validators   = map[string]ValidatorFunc{}
+)
+
+/*
+RegisterValidator registra una regla personalizada que se ejecuta en CheckInsertGeneric y CheckUpdateGeneric
+en los campos que la declaran, si ya existe una regla con el mismo nombre se reemplaza.
+
+	Parámetros
+		* name {string}: nombre de la regla usado en la etiqueta validateType
+		* fn {ValidatorFunc}: función de validación
+*/
+func RegisterValidator(name string, fn ValidatorFunc) {
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	if fn == nil {
+		delete(validators, name)
+		return
+	}
+	validators[name] = fn
+}
+
+func validator(name string) (ValidatorFunc, bool) {
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+	fn, ok := validators[name]
+	return fn, ok
+}
+
+// EntityContext retorna la entidad que se esta validando, permite que una regla personalizada consulte otros campos
+func EntityContext(ctx context.Context) (any, bool) {
+	entity := ctx.Value(entityKey{})
+	return entity, entity != nil
+}
+
+// withEntity agrega al contexto la entidad y la acción que se validan
+func withEntity(ctx context.Context, entity any, action Actions) context.Context {
+	return context.WithValue(context.WithValue(ctx, entityKey{}, entity), actionKey{}, action)
+}
+
+// parseCustomRule interpreta las reglas `custom=nombre` y `fn=nombre(param1,param2)` de validateType
+func parseCustomRule(kind string, param string) (CustomRule, error) {
+	if kind == "custom" {
+		if param == "" {
+			return CustomRule{}, errors.New("necesita el nombre de la regla")
+		}
+		return CustomRule{Name: param}, nil
+	}
+	name, args, hasArgs := strings.Cut(param, "(")
+	custom := CustomRule{Name: strings.TrimSpace(name)}
+	if custom.Name == "" {
+		return CustomRule{}, errors.New("necesita el nombre de la regla")
+	}
+	if hasArgs {
+		args, closed := strings.CutSuffix(strings.TrimSpace(args), ")")
+		if !closed {
+			return CustomRule{}, errors.New("falta cerrar los parámetros con )")
+		}
+		for _, arg := range strings.Split(args, ",") {
+			if arg = strings.TrimSpace(arg); arg != "" {
+				custom.Params = append(custom.Params, arg)
+			}
+		}
+	}
+	return custom, nil
+}
+
+// customValidations ejecuta las reglas personalizadas del campo
+func customValidations(ctx context.Context, item Fields, value any) []ruleError {
+	var errs []ruleError
+	for _, rule := range item.Validators {
+		params := make([]any, len(rule.Params))
+		for i, p := range rule.Params {
+			params[i] = p
+		}
+		fn, ok := validator(rule.Name)
+		if !ok {
+			errs = append(errs, ruleError{rule: rule.Name, code: "validate.custom.unknown", params: params, args: []any{rule.Name}})
+			continue
+		}
+		if err := fn(ctx, value, rule.Params); err != nil {
+			errs = append(errs, ruleError{rule: rule.Name, code: "validate.custom", params: params, args: []any{err.Error()}})
+		}
+	}
+	return errs
+}
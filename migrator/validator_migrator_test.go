@@ -0,0 +1,47 @@
+package migrator_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/deybin/pgorm/migrator"
+)
+
+type documentItem struct {
+	Id       string `validate:"primaryKey;required"`
+	Kind     string `validate:"required;update" validateType:"case=uppercase"`
+	Document string `validate:"required;update" validateType:"fn=document(11)"`
+}
+
+func (e documentItem) Name() string      { return "document_items" }
+func (e documentItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e documentItem) Values() []any     { return migrator.EntityValues(e) }
+
+func TestRegisterValidator(t *testing.T) {
+	// el documento de una empresa (RUC) tiene la longitud del parámetro, el de una persona (DNI) 8 dígitos
+	migrator.RegisterValidator("document", func(ctx context.Context, value any, params []string) error {
+		entity, _ := migrator.EntityContext(ctx)
+		length, _ := strconv.Atoi(params[0])
+		if entity.(*documentItem).Kind != "RUC" {
+			length = 8
+		}
+		if len(value.(string)) != length {
+			return fmt.Errorf("debe tener %d dígitos", length)
+		}
+		return nil
+	})
+	defer migrator.RegisterValidator("document", nil)
+
+	fields := schema[documentItem]{}.ParseInsert()
+	if _, err := migrator.CheckInsertGeneric(fields, &documentItem{Id: "1", Kind: "RUC", Document: "20123456789"}); err != nil {
+		t.Errorf("no se esperaba este error: %v", err)
+	}
+	_, err := migrator.CheckInsertGeneric(fields, &documentItem{Id: "1", Kind: "DNI", Document: "20123456789"})
+	var verrs migrator.ValidationErrors
+	if !errors.As(err, &verrs) || verrs[0].Rule != "document" || verrs[0].Params[0] != "11" {
+		t.Errorf("se esperaba la falla de la regla document: %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/deybin/pgorm/internal/adapters"
 	"github.com/deybin/pgorm/internal/core/domain"
+	"github.com/deybin/pgorm/internal/core/mapper"
 	"github.com/deybin/pgorm/internal/core/ports"
 	"github.com/deybin/pgorm/internal/core/services"
 	"github.com/deybin/pgorm/migrator"
@@ -29,18 +30,43 @@ func NewQuery() *services.Query {
 
 func ExecQuery[T any](db ports.DBPort, ctx context.Context, q *services.Query) (T, error) {
 	var dest T
-	err := db.ExecuteWithPgxScan(ctx, &dest, q.String(), q.Sintaxis.Arguments()...)
+	var err error
+	if q.Sintaxis.Nested_field {
+		err = execNested(db, ctx, &dest, q)
+	} else {
+		err = db.ExecuteWithPgxScan(ctx, &dest, q.String(), q.Sintaxis.Arguments()...)
+	}
+	if err == nil {
+		err = services.Preload(db, ctx, &dest, q.Sintaxis.Model_field, q.Sintaxis.Deleted_field, q.Sintaxis.Preload_field...)
+	}
 	q.Sintaxis = &domain.Sintaxis{}
 	return dest, err
 }
 
 func ExecQueryWithSchema[T any](db ports.DBPort, schema string, ctx context.Context, q *services.Query) (T, error) {
 	var dest T
-	err := db.ExecuteWithPgxScanAndSchema(schema, ctx, &dest, q.String(), q.Sintaxis.Arguments()...)
+	var err error
+	if q.Sintaxis.Nested_field {
+		err = execNested(db, context.WithValue(ctx, adapters.SchemaId, schema), &dest, q)
+	} else {
+		err = db.ExecuteWithPgxScanAndSchema(schema, ctx, &dest, q.String(), q.Sintaxis.Arguments()...)
+	}
+	if err == nil {
+		err = services.Preload(db, context.WithValue(ctx, adapters.SchemaId, schema), &dest, q.Sintaxis.Model_field, q.Sintaxis.Deleted_field, q.Sintaxis.Preload_field...)
+	}
 	q.Sintaxis.Reset()
 	return dest, err
 }
 
+// execNested ejecuta la consulta obteniendo filas planas y las agrupa en structs anidados
+func execNested(db ports.DBPort, ctx context.Context, dest any, q *services.Query) error {
+	rows, err := db.Execute(ctx, q.String(), q.Sintaxis.Arguments()...)
+	if err != nil {
+		return err
+	}
+	return mapper.Nested(rows, dest)
+}
+
 //Procedure
 
 func ExecProcedure(db ports.DBPort, ctx context.Context, q *services.Query) error {
@@ -60,12 +86,29 @@ func NewSqlExecSingles(s migrator.Schema, datos ...migrator.Entity) *services.Sq
 	}
 }
 
-func ExecTransaction(db ports.DBPort, ctx context.Context, s *services.SqlExecSingles) error {
-	return db.ExecuteTransactions(ctx, s.Transactions.Query()...)
+/*
+ExecTransaction ejecuta las sentencias generadas por s dentro de una transacción.
+
+	Return
+		- (adapters.Results): resultado de cada sentencia (acción, tabla, registros afectados y registros devueltos por RETURNING)
+		- (error): retorna el error de la sentencia que fallo (la transacción se revierte) o el error de los hooks After ejecutados después del commit
+*/
+func ExecTransaction(db ports.DBPort, ctx context.Context, s *services.SqlExecSingles) (adapters.Results, error) {
+	results, err := db.ExecuteTransactions(ctx, s.Transactions.Query()...)
+	if err != nil {
+		return nil, err
+	}
+	s.SetResults(results)
+	return results, s.AfterHooks(ctx)
 }
 
-func ExecTransactionWithSchema(db ports.DBPort, schema string, ctx context.Context, s *services.SqlExecSingles) error {
-	return db.ExecuteTransactionsWithSchema(schema, ctx, s.Transactions.Query()...)
+func ExecTransactionWithSchema(db ports.DBPort, schema string, ctx context.Context, s *services.SqlExecSingles) (adapters.Results, error) {
+	results, err := db.ExecuteTransactionsWithSchema(schema, ctx, s.Transactions.Query()...)
+	if err != nil {
+		return nil, err
+	}
+	s.SetResults(results)
+	return results, s.AfterHooks(ctx)
 }
 
 // CRUD MULTI
@@ -74,12 +117,37 @@ func NewSqlExecMultiples() *services.SqlExecMultiples {
 	return &services.SqlExecMultiples{}
 }
 
-func ExecTransactionMulti(db ports.DBPort, ctx context.Context, s *services.SqlExecMultiples) error {
-	return db.ExecuteTransactionsMulti(ctx, s.DataExec()...)
+/*
+ExecTransactionMulti ejecuta las sentencias de todas las transacciones de s dentro de una única transacción.
+
+	Return
+		- ([]adapters.Results): resultados agrupados en el mismo orden que s.GetTransactions()
+		- (error): retorna el error de la sentencia que fallo (la transacción se revierte) o el error de los hooks After ejecutados después del commit
+*/
+func ExecTransactionMulti(db ports.DBPort, ctx context.Context, s *services.SqlExecMultiples) ([]adapters.Results, error) {
+	results, err := db.ExecuteTransactionsMulti(ctx, s.DataExec()...)
+	if err != nil {
+		return nil, err
+	}
+	s.SetResults(results)
+	return groupResults(results), s.AfterHooks(ctx)
 }
 
-func ExecTransactionMultiWithSchema(db ports.DBPort, schema string, ctx context.Context, s *services.SqlExecMultiples) error {
-	return db.ExecuteTransactionsMultiWithSchema(schema, ctx, s.DataExec()...)
+func ExecTransactionMultiWithSchema(db ports.DBPort, schema string, ctx context.Context, s *services.SqlExecMultiples) ([]adapters.Results, error) {
+	results, err := db.ExecuteTransactionsMultiWithSchema(schema, ctx, s.DataExec()...)
+	if err != nil {
+		return nil, err
+	}
+	s.SetResults(results)
+	return groupResults(results), s.AfterHooks(ctx)
+}
+
+func groupResults(results [][]adapters.ResultExec) []adapters.Results {
+	grouped := make([]adapters.Results, len(results))
+	for i, v := range results {
+		grouped[i] = v
+	}
+	return grouped
 }
 
 //Generator
@@ -29,6 +29,9 @@ const (
 	NOT_IN      = clause.NOT_IN
 	BETWEEN     = clause.BETWEEN
 	NOT_BETWEEN = clause.NOT_BETWEEN
+	IS_NULL     = clause.IS_NULL
+	IS_NOT_NULL = clause.IS_NOT_NULL
+	ANY         = clause.ANY
 )
 
 type DBPort = ports.DBPort
@@ -36,3 +39,15 @@ type DBPort = ports.DBPort
 type ConfigPgxAdapter = adapters.ConfigPgxAdapter
 
 const SchemaId = adapters.SchemaId
+
+const ActorId = adapters.ActorId
+
+type StatementError = adapters.StatementError
+
+type ErrStaleEntity = adapters.ErrStaleEntity
+
+type ErrRowsAffected = adapters.ErrRowsAffected
+
+type ResultExec = adapters.ResultExec
+
+type Results = adapters.Results
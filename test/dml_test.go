@@ -2,6 +2,7 @@ package test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/deybin/pgorm/internal/adapters"
 	"github.com/deybin/pgorm/migrator"
 	tables "github.com/deybin/pgorm/test/table"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 // inserta,actualiza y elimina datos solo de una tabla
@@ -43,7 +45,7 @@ func TestCRUD_IUD_Single(t *testing.T) {
 		return
 	}
 
-	if err = pgorm.ExecTransaction(db, ctx, crudInsert); err != nil {
+	if _, err = pgorm.ExecTransaction(db, ctx, crudInsert); err != nil {
 		t.Errorf("se esperaba este error: %s", err.Error())
 		return
 	}
@@ -58,17 +60,21 @@ func TestCRUD_IUD_Single(t *testing.T) {
 		},
 	}
 
-	crudUpdate := pgorm.NewSqlExecSingles(&tables.ModelsSchema{}, dataUpdate)
+	crudUpdate := pgorm.NewSqlExecSingles(&tables.ModelsSchema{}, dataUpdate).ExpectRows(1)
 
 	if err := crudUpdate.Update(); err != nil {
 		t.Errorf("se esperaba este error: %s", err.Error())
 		return
 	}
 
-	if err := pgorm.ExecTransaction(db, ctx, crudUpdate); err != nil {
+	results, err := pgorm.ExecTransaction(db, ctx, crudUpdate)
+	if err != nil {
 		t.Errorf("se esperaba este error: %s", err.Error())
 		return
 	}
+	if results.RowsAffected() != 1 || results[0].Action != adapters.UPDATE {
+		t.Errorf("resultado inesperado: %+v", results)
+	}
 
 	time.Sleep(10 * time.Second)
 
@@ -83,7 +89,7 @@ func TestCRUD_IUD_Single(t *testing.T) {
 		return
 	}
 
-	if err := pgorm.ExecTransaction(db, ctx, crudDelete); err != nil {
+	if _, err := pgorm.ExecTransaction(db, ctx, crudDelete); err != nil {
 		t.Errorf("se esperaba este error: %s", err.Error())
 		return
 	}
@@ -196,7 +202,7 @@ func TestCRUD_IUD_MultipleSuccess(t *testing.T) {
 
 	crud.SetTransactions(crudDelete)
 
-	if err := pgorm.ExecTransactionMulti(db, context.Background(), crud); err != nil {
+	if _, err := pgorm.ExecTransactionMulti(db, context.Background(), crud); err != nil {
 		t.Errorf("se esperaba este error: %s", err.Error())
 		return
 	}
@@ -308,8 +314,9 @@ func TestCRUD_IUD_MultipleError(t *testing.T) {
 	}
 
 	crud.SetTransactions(crudDelete)
-	if err := pgorm.ExecTransactionMulti(db, context.Background(), crud); err != nil {
-		if err.Error() == "ERROR: value too long for type character varying(11) (SQLSTATE 22001)" {
+	if _, err := pgorm.ExecTransactionMulti(db, context.Background(), crud); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Error() == "ERROR: value too long for type character varying(11) (SQLSTATE 22001)" {
 			fmt.Println("Error OK!!!: ", err.Error())
 			return
 		}
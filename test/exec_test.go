@@ -0,0 +1,105 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/deybin/pgorm"
+	"github.com/deybin/pgorm/internal/adapters"
+	"github.com/deybin/pgorm/migrator"
+)
+
+type execItem struct {
+	Id     string `json:"id" db:"id" validate:"primaryKey;required"`
+	Nombre string `json:"nombre" db:"nombre" validate:"required;update"`
+	Stock  int64  `json:"stock" db:"stock" validate:"update"`
+}
+
+func (e execItem) Name() string      { return "pgorm_exec_items" }
+func (e execItem) Columns() []string { return migrator.EntityColumns(e) }
+func (e execItem) Values() []any     { return migrator.EntityValues(e) }
+
+type execItemSchema struct{ table execItem }
+
+func (s execItemSchema) Table() migrator.Entity { return s.table }
+func (s execItemSchema) ParseInsert() []migrator.Fields {
+	return migrator.GenerateSchema(s.table, migrator.INSERT)
+}
+func (s execItemSchema) ParseUpdate() []migrator.Fields {
+	return migrator.GenerateSchema(s.table, migrator.UPDATE)
+}
+func (s execItemSchema) ParseDelete() []migrator.Fields {
+	return migrator.GenerateSchema(s.table, migrator.DELETE)
+}
+
+// execDB abre la conexión y crea la tabla de prueba, el test se omite si no hay una base de datos disponible
+func execDB(t *testing.T) (*adapters.PgxAdapter, context.Context) {
+	t.Helper()
+	db, err := adapters.NewPool(adapters.ConfigPgxAdapter{})
+	if err != nil {
+		t.Skipf("sin conexión a la base de datos: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := db.Pool().Exec(ctx, "CREATE TABLE IF NOT EXISTS pgorm_exec_items (id text PRIMARY KEY, nombre text NOT NULL, stock bigint NOT NULL DEFAULT 5)"); err != nil {
+		db.Pool().Close()
+		t.Skipf("no se pudo crear la tabla de prueba: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = db.Pool().Exec(ctx, "DROP TABLE IF EXISTS pgorm_exec_items")
+		db.Pool().Close()
+	})
+	return db, ctx
+}
+
+func TestExec_BatchCopyReturning(t *testing.T) {
+	db, ctx := execDB(t)
+
+	// varias sentencias INSERT se envían en un solo pgx.Batch
+	batch := pgorm.NewSqlExecSingles(execItemSchema{}, execItem{Id: "1", Nombre: "teclado", Stock: 3}, execItem{Id: "2", Nombre: "mouse", Stock: 8})
+	if err := batch.Insert(); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	results, err := pgorm.ExecTransaction(db, ctx, batch)
+	if err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	if len(results) != 2 || results.RowsAffected() != 2 {
+		t.Errorf("resultado inesperado del batch: %+v", results)
+	}
+
+	// los registros con las mismas columnas se copian con COPY FROM
+	copied := pgorm.NewSqlExecSingles(execItemSchema{}, execItem{Id: "3", Nombre: "monitor", Stock: 1}, execItem{Id: "4", Nombre: "parlante", Stock: 2}, execItem{Id: "5", Nombre: "cable"})
+	if err := copied.BulkInsert(); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	results, err = pgorm.ExecTransaction(db, ctx, copied)
+	if err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	if len(results) != 2 || results.RowsAffected() != 3 {
+		t.Errorf("resultado inesperado del COPY: %+v", results)
+	}
+
+	// RETURNING devuelve los valores asignados por la base de datos (DEFAULT de stock)
+	returned := pgorm.NewSqlExecSingles(execItemSchema{}, execItem{Id: "6", Nombre: "camara"}).Returning("id", "stock")
+	if err := returned.Insert(); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	if _, err := pgorm.ExecTransaction(db, ctx, returned); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	var items []execItem
+	if err := returned.Scan(&items); err != nil {
+		t.Fatalf("no se esperaba este error: %v", err)
+	}
+	if len(items) != 1 || items[0].Id != "6" || items[0].Stock != 5 {
+		t.Errorf("registros devueltos inesperados: %+v", items)
+	}
+
+	var total []struct {
+		Count int64 `db:"count"`
+	}
+	if err := db.ExecuteWithPgxScan(ctx, &total, "SELECT count(*) FROM pgorm_exec_items"); err != nil || len(total) != 1 || total[0].Count != 6 {
+		t.Errorf("se esperaba 6 registros: %v %v", total, err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/deybin/pgorm"
 	"github.com/deybin/pgorm/internal/adapters"
 	"github.com/deybin/pgorm/internal/core/clause"
+	"github.com/deybin/pgorm/internal/core/mapper"
 
 	tables "github.com/deybin/pgorm/test/table"
 )
@@ -111,6 +112,14 @@ func Test_Query__Sintaxis(t *testing.T) {
 	}
 	fmt.Println("sintaxis OK: ", queryString)
 	querySql.Reset()
+
+	queryString = querySql.Select().From(tables.Models{}.Name()).Where("birthdate", clause.IS_NULL, nil).And("id", clause.ANY, []string{"1", "2"}).String()
+	if strings.TrimSpace(queryString) != "SELECT * FROM models WHERE birthdate IS NULL AND id = ANY($1)" {
+		t.Errorf("query inesperado: %q", queryString)
+		return
+	}
+	fmt.Println("sintaxis OK: ", queryString)
+	querySql.Reset()
 }
 
 func Test_Query__Response(t *testing.T) {
@@ -312,3 +321,53 @@ func Test_Query_Contexto(t *testing.T) {
 		t.Errorf("No se esperaba este error: %v", err)
 	}
 }
+
+func Test_Query__NestedMapping(t *testing.T) {
+	type customer struct {
+		Id   string `db:"id"`
+		Name string `db:"name"`
+	}
+	type line struct {
+		Id     int64   `db:"id" validate:"primaryKey"`
+		Amount float64 `db:"amount"`
+	}
+	type order struct {
+		Id       string    `db:"id" validate:"primaryKey"`
+		Total    float64   `db:"total"`
+		Customer customer  `db:"customer"`
+		Seller   *customer `prefix:"seller_"`
+		Lines    []line    `db:"lines"`
+	}
+
+	rows := []map[string]any{
+		{"id": "a", "total": 10.5, "customer.id": "c1", "customer.name": "ana", "seller_id": nil, "seller_name": nil, "lines.id": int32(1), "lines.amount": 4.5},
+		{"id": "a", "total": 10.5, "customer.id": "c1", "customer.name": "ana", "seller_id": nil, "seller_name": nil, "lines.id": int32(2), "lines.amount": 6.0},
+		{"id": "a", "total": 10.5, "customer.id": "c1", "customer.name": "ana", "seller_id": nil, "seller_name": nil, "lines.id": int32(2), "lines.amount": 6.0},
+		{"id": "b", "total": 0.0, "customer.id": "c2", "customer.name": "luis", "seller_id": "s1", "seller_name": "rosa", "lines.id": nil, "lines.amount": nil},
+	}
+
+	var orders []order
+	if err := mapper.Nested(rows, &orders); err != nil {
+		t.Errorf("no se esperaba este error: %s", err.Error())
+		return
+	}
+
+	if len(orders) != 2 {
+		t.Errorf("se esperaba 2 ordenes, pero se obtuvo %d", len(orders))
+		return
+	}
+	if orders[0].Customer.Name != "ana" || len(orders[0].Lines) != 2 || orders[0].Lines[1].Amount != 6.0 {
+		t.Errorf("mapeo inesperado: %+v", orders[0])
+	}
+	if orders[0].Seller != nil {
+		t.Errorf("no se esperaba vendedor: %+v", orders[0].Seller)
+	}
+	if len(orders[1].Lines) != 0 || orders[1].Seller == nil || orders[1].Seller.Name != "rosa" {
+		t.Errorf("mapeo inesperado: %+v", orders[1])
+	}
+
+	var one order
+	if err := mapper.Nested(rows[3:], &one); err != nil || one.Id != "b" {
+		t.Errorf("mapeo inesperado: %+v, %v", one, err)
+	}
+}
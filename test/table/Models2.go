@@ -14,7 +14,7 @@ type ModelsSchema2 struct {
 }
 
 type Models2 struct {
-	Atcreate   *time.Time ` json:"atcreate" tag:"atcreate"  validate:"required" validateType:"" `
+	Atcreate   *time.Time ` json:"atcreate" tag:"atcreate"  validate:"required;autoCreateTime" validateType:"" `
 	Birthdate  *time.Time ` json:"birthdate" tag:"birthdate"  validate:"required" validateType:"" `
 	Age        uint64     ` json:"age" tag:"age"  validate:"required;update" validateType:"max=100" `
 	Amount     float64    ` json:"amount" tag:"amount"  validate:"required" validateType:"" `